@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// aksCmd represents the aks command
+var aksCmd = &cobra.Command{
+	Use:   "aks",
+	Short: "Verify the prerequisites of an Azure Kubernetes Service cluster in a location",
+	Long: `The aks command provides the means to verify an Azure Kubernetes Service cluster can be deployed to a location.
+The Kubernetes version must be offered in the location, and the node VM size must be offered and not restricted
+for the subscription in the location and the requested availability zones.
+The latest generally available Kubernetes version of the location is verified if no version is given.`,
+	Example: `  azure-resource-verifier aks -s <subscription-id> -l eastus2 --kubernetes-version 1.29 --node-vm-size Standard_D4s_v5 --zones 1,2,3`,
+
+	RunE: cli.AzureClientWrapRunE(aksCommand),
+}
+
+func aksCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("aks called")
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	zones, err := cmd.Flags().GetStringSlice("zones")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing zones flag", err)
+	}
+
+	requirements := azure.KubernetesRequirements{
+		KubernetesVersion: viper.GetString("kubernetes-version"),
+		NodeVmSize:        viper.GetString("node-vm-size"),
+		Zones:             zones,
+	}
+
+	locations, err := getLocations(cmd, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	azureKubernetesService := azure.NewAzureKubernetesService(session)
+
+	aksResults, err := azureKubernetesService.GetKubernetesLocations(ctx, locations, requirements)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting Azure Kubernetes Service locations", err)
+	}
+
+	if err := attachQuotas(ctx, session, azure.KubernetesService, locations, aksResults); err != nil {
+		return cli.CreateAzrErr("Error getting Azure Kubernetes Service quota usages", err)
+	}
+
+	if err := verifyPolicies(cmd, ctx, session, aksResults, requirements.NodeVmSize); err != nil {
+		return err
+	}
+
+	if err := verifyRegistrations(cmd, ctx, session, aksResults); err != nil {
+		return err
+	}
+
+	return renderTable(cmd, newResultsTable(table.KubernetesService, aksResults))
+}
+
+func init() {
+	rootCmd.AddCommand(aksCmd)
+
+	aksCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+
+	aksCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
+	aksCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
+	aksCmd.MarkFlagsOneRequired("location", "all-locations")
+	aksCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	addVerificationFlags(aksCmd)
+
+	aksCmd.Flags().String("kubernetes-version", "", "The Kubernetes version, e.g. 1.29 or 1.29.2. Defaults to the latest generally available version of the location")
+	aksCmd.Flags().String("node-vm-size", "", "The Virtual Machine size of the nodes, e.g. Standard_D4s_v5")
+	aksCmd.Flags().StringSlice("zones", []string{}, "The availability zones of the nodes, e.g. 1,2,3. Requires --node-vm-size")
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azd"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/manifest"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// azdCmd represents the azd command
+var azdCmd = &cobra.Command{
+	Use:   "azd [project-dir]",
+	Short: "Verify the services of an Azure Developer CLI (azd) project can be deployed to a location",
+	Long: `The azd command reads an azd project and verifies, without any prompts, that its services can be deployed to a location.
+
+The services are detected from the project:
+  the hosting targets of azure.yaml: appservice, function, containerapp, staticwebapp and aks
+  the resources of azure.yaml: db.postgres, db.mysql, db.redis, db.mongo and host.containerapp
+  the databases referenced by the parameter names of the infra module, e.g. postgresDatabaseName
+    in infra/main.parameters.json: postgres, mysql, redis, cosmos and sql
+
+The subscription and the location default to AZURE_SUBSCRIPTION_ID and AZURE_LOCATION of the azd environment,
+i.e. .azure/<environment>/.env. The default environment of the project is used if --environment isn't given.
+Without a subscription in the azd environment, the subscription defaults to the AZURE_SUBSCRIPTION_ID environment
+variable or the default subscription of the Azure CLI.`,
+	Example: `  azure-resource-verifier azd
+  azure-resource-verifier azd ./todo-python-mongo -e dev --all-locations`,
+	Args: cobra.MaximumNArgs(1),
+
+	RunE: cli.AzureClientWrapRunE(azdCommand),
+}
+
+func azdCommand(cmd *cobra.Command, args []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("azd called")
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	environment, err := cmd.Flags().GetString("environment")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing environment flag", err)
+	}
+
+	project, err := azd.LoadProject(dir, environment)
+	if err != nil {
+		return cli.CreateAzrErr("Error reading the azd project", err)
+	}
+
+	subscriptionId, err := cmd.Flags().GetString("subscription-id")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing subscription-id flag", err)
+	}
+	locationNames, err := cmd.Flags().GetStringArray("location")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	// The flags override the variables of the environment
+	if project.Environment != nil {
+		log.Printf("Using azd environment: %s", project.Environment.Name)
+		subscriptionId = defaultString(subscriptionId, project.Environment.SubscriptionId())
+		if len(locationNames) == 0 && project.Environment.Location() != "" {
+			locationNames = []string{project.Environment.Location()}
+		}
+	}
+
+	if subscriptionId == "" {
+		if subscriptionId, err = getSubscriptionId(); err != nil {
+			return err
+		}
+	} else {
+		log.Printf("subscription-id: %s", subscriptionId)
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	allLocations := viper.GetBool("all-locations")
+	if len(locationNames) == 0 && !allLocations {
+		return cli.CreateAzrErr("Error getting the location", errors.New("no location: use --location, --all-locations or set "+azd.LocationVariable+" in the azd environment"))
+	}
+
+	if len(project.Services) == 0 {
+		return cli.CreateAzrErr("Error reading the azd project", errors.New("no services to verify were detected in the azd project"))
+	}
+
+	checks := make([]*serviceCheck, 0, len(project.Services))
+	for _, service := range project.Services {
+		check, err := newServiceCheckFromManifest(service, manifest.Constraints{})
+		if err != nil {
+			return cli.CreateAzrErr("Error reading the azd project", err)
+		}
+		cmd.PrintErrf("Detected: %s (%s)\n", check.name, defaultString(check.resourceType, check.service))
+		checks = append(checks, check)
+	}
+
+	azureLocations, err := getAllLocationsFromSubscription(ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting the locations", err)
+	}
+
+	if !allLocations {
+		azureLocations = filterLocations(azureLocations, locationNames)
+	}
+
+	results, err := runServiceChecks(checks, azureLocations, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error verifying services", err)
+	}
+
+	if err := verifyServiceResults(cmd, ctx, session, checks, results); err != nil {
+		return err
+	}
+
+	deployableLocations := azureLocations
+	allResults := &azure.VerificationResultList{}
+	for _, result := range results {
+		deployableLocations = deployableLocations.Intersection(result.DeployableLocations())
+		allResults.Append(result)
+	}
+
+	names := make([]string, 0, len(deployableLocations.Value))
+	for _, location := range deployableLocations.Value {
+		names = append(names, location.Name)
+	}
+	cmd.PrintErrf("Locations supporting all services: %s\n", strings.Join(names, ", "))
+
+	return renderTable(cmd, newResultsTable(table.MultipleServices, allResults))
+}
+
+func init() {
+	rootCmd.AddCommand(azdCmd)
+
+	// The subscription can come from the azd environment
+	azdCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID of the azd environment, then the default subscription of the Azure CLI")
+
+	azdCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times. Defaults to AZURE_LOCATION of the azd environment")
+	azdCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
+	azdCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	addVerificationFlags(azdCmd)
+
+	azdCmd.Flags().StringP("environment", "e", "", "The azd environment to read the subscription and the location from. Defaults to the default environment of the project")
+}
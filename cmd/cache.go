@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/nickdala/azure-resource-verifier/internal/cache"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of the responses of the Azure APIs",
+	Long: `The cache command manages the cache of the responses of the Azure APIs, in the user cache directory.
+The locations of the subscriptions, the resource types of the providers and the capabilities of the services
+in each region are cached until their time to live expires, from 6 to 24 hours.
+Use --refresh to call the Azure APIs again, or --no-cache to neither read nor write the cache.`,
+}
+
+// cacheInfoCmd represents the cache info command
+var cacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the directory, the number of entries and the size of the cache",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		// Silence usage so we don't print the usage when an error occurs
+		cmd.SilenceUsage = true
+
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return fmt.Errorf("error binding flags: %s", err)
+		}
+
+		c, err := cache.New(cache.Options{})
+		if err != nil {
+			return cli.CreateAzrErr("Error opening the cache", err)
+		}
+
+		info, err := c.Info()
+		if err != nil {
+			return cli.CreateAzrErr("Error reading the cache", err)
+		}
+
+		t := table.NewTable(table.CacheInfo)
+		t.AppendRow([]string{info.Dir, strconv.Itoa(info.Entries), strconv.Itoa(info.Expired), formatSize(info.Size)})
+		t.SetData(info)
+
+		return renderTable(cmd, t)
+	},
+}
+
+// cacheClearCmd represents the cache clear command
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all the entries of the cache",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		// Silence usage so we don't print the usage when an error occurs
+		cmd.SilenceUsage = true
+
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return fmt.Errorf("error binding flags: %s", err)
+		}
+
+		c, err := cache.New(cache.Options{})
+		if err != nil {
+			return cli.CreateAzrErr("Error opening the cache", err)
+		}
+
+		removed, err := c.Clear()
+		if err != nil {
+			return cli.CreateAzrErr("Error clearing the cache", err)
+		}
+
+		cmd.PrintErrf("Removed %d entries from %s\n", removed, c.Dir())
+		return nil
+	},
+}
+
+// This function is used to format a size in bytes, e.g. 1.5 MiB.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheInfoCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
@@ -2,18 +2,122 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// This function is used to resolve the subscription to verify: the --subscription-id flag, then the AZURE_SUBSCRIPTION_ID
+// environment variable or the subscription-id of the config file, then the default subscription of the Azure CLI.
+func getSubscriptionId() (string, error) {
+	if subscriptionId := viper.GetString("subscription-id"); subscriptionId != "" {
+		log.Printf("subscription-id: %s", subscriptionId)
+		return subscriptionId, nil
+	}
+
+	subscription, err := azure.GetCliDefaultSubscription()
+	if err != nil {
+		return "", cli.CreateAzrErr("No subscription. Use --subscription-id, set AZURE_SUBSCRIPTION_ID or run az account set", err)
+	}
+
+	log.Printf("subscription-id: %s (the default subscription of the Azure CLI)", subscription.Id)
+	return subscription.Id, nil
+}
+
+// This function is used to add the flags shared by the verification commands: the scope of the Azure Policy
+// assignments and the registration of the resource providers.
+func addVerificationFlags(cmd *cobra.Command) {
+	cmd.Flags().String("resource-group", "", "The resource group to evaluate the Azure Policy assignments for, instead of the subscription")
+	cmd.Flags().String("management-group", "", "The management group to evaluate the Azure Policy assignments for, instead of the subscription")
+	cmd.MarkFlagsMutuallyExclusive("resource-group", "management-group")
+
+	cmd.Flags().Bool("fix", false, "Whether to register the resource providers that are not registered in the subscription, after a confirmation")
+}
+
+// This function is used to add the quota headroom of the service to the verdict of the locations.
+// The deployable locations whose quota usages can't be read are marked as unknown.
+func attachQuotas(ctx context.Context, session *azure.Session, service string, locations *azure.AzureLocationList, results *azure.VerificationResultList) error {
+	quotas := azure.ServiceQuotas(service)
+	if len(quotas) == 0 {
+		return nil
+	}
+
+	usages, errs, err := azure.NewAzureQuota(session).GetQuotaUsages(ctx, locations, azure.QuotaProvidersOf(quotas))
+	if err != nil {
+		return err
+	}
+	results.AttachQuotas(ctx, usages, errs, quotas)
+
+	return nil
+}
+
+// This function is used to render the table to stdout in the format selected with the --output flag.
+// Everything else (banners, logs, prompts) is written to stderr so that stdout can be piped into other tools.
+// The format is read from the flags of the command, so that it doesn't depend on the flags being bound to viper.
+func renderTable(cmd *cobra.Command, t *table.Table) error {
+	output, err := cmd.Flags().GetString(outputFormatChoice.Name)
+	if err != nil {
+		return err
+	}
+
+	format := table.Format(output)
+	if err := t.Render(cmd.OutOrStdout(), format); err != nil {
+		return cli.CreateAzrErr("Error rendering the output", err)
+	}
+
+	return nil
+}
+
+// This function is used to create the table for the verification results of a command. Every layout shows
+// the reason and the details of the verdict, e.g. the policy assignment that blocks the location.
+// The structured output formats (json, yaml) contain the full verification results.
+func newResultsTable(layout table.TableLayout, results *azure.VerificationResultList) *table.Table {
+	t := table.NewTable(layout)
+
+	for _, result := range results.Value {
+		enabled := strconv.FormatBool(result.IsDeployable())
+
+		switch layout {
+		case table.PostgreSqlService:
+			t.AppendRow([]string{result.Location.Name, result.Location.DisplayName, enabled, result.FeatureString(azure.HighAvailabilityFeature), result.ReasonCode, result.Evidence, result.QuotaString()})
+		case table.MySqlService:
+			t.AppendRow([]string{result.Location.Name, result.Location.DisplayName, enabled, result.FeatureString(azure.HighAvailabilityFeature), result.ReasonCode, result.Evidence})
+		case table.SqlDatabaseService:
+			t.AppendRow([]string{result.Location.Name, result.Location.DisplayName, enabled, string(result.Status), result.FeatureString(azure.ZoneRedundancyFeature), result.FeatureString(azure.ServerlessFeature), result.ReasonCode, result.Evidence})
+		case table.KubernetesService:
+			t.AppendRow([]string{result.Location.Name, result.Location.DisplayName, enabled, result.FeatureString(azure.ZoneRedundancyFeature), result.ReasonCode, result.Evidence, result.QuotaString()})
+		case table.VirtualMachineSku:
+			t.AppendRow([]string{result.Location.Name, result.Location.DisplayName, enabled, string(result.Status), strings.Join(result.Zones, ", "), result.ReasonCode, result.Evidence, result.QuotaString()})
+		case table.ResourceType:
+			t.AppendRow([]string{result.Location.Name, result.Location.DisplayName, enabled, strings.Join(result.Zones, ", "), azure.LatestApiVersion(result.ApiVersions), result.ReasonCode, result.Evidence})
+		case table.MultipleServices:
+			t.AppendRow([]string{result.Service, result.Location.Name, enabled, result.FeatureString(azure.HighAvailabilityFeature), result.ReasonCode, result.Evidence, result.QuotaString()})
+		case table.MultipleSubscriptions:
+			t.AppendRow([]string{result.Subscription.Name(), result.Service, result.Location.Name, enabled, result.ReasonCode, result.Evidence, result.QuotaString()})
+		default:
+			t.AppendRow([]string{result.Location.Name, result.Location.DisplayName, enabled, result.ReasonCode, result.Evidence})
+		}
+	}
+
+	t.SetData(results.Value)
+
+	return t
+}
+
 // This function is used to get the locations from the command line flags or from the Azure subscription
 // if the --location flag is not provided. If the --location flag is provided, the locations are filtered
 // based on the locations provided in the flag.
-func getLocations(cmd *cobra.Command, cred *azidentity.DefaultAzureCredential, ctx context.Context, subscriptionId string) (*azure.AzureLocationList, error) {
+func getLocations(cmd *cobra.Command, ctx context.Context, session *azure.Session) (*azure.AzureLocationList, error) {
 
-	azureLocations, err := getAllLocationsFromSubscription(cred, ctx, subscriptionId)
+	azureLocations, err := getAllLocationsFromSubscription(ctx, session)
 	if err != nil {
 		return nil, err
 	}
@@ -23,12 +127,33 @@ func getLocations(cmd *cobra.Command, cred *azidentity.DefaultAzureCredential, c
 		return nil, err
 	}
 
-	// If the --location flag is not provided, return all locations
+	return filterLocations(azureLocations, locations), nil
+}
+
+// This function is used to check that the names are locations of the subscription, e.g. the candidate
+// locations of the manifest, so that a misspelled location isn't silently left out.
+func requireLocations(azureLocations *azure.AzureLocationList, names []string) error {
+	unknown := []string{}
+	for _, name := range names {
+		if !slices.ContainsFunc(azureLocations.Value, func(location *azure.AzureLocation) bool { return location.Name == name }) {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown locations %s: the locations are not available in the subscription", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// This function is used to filter the locations by name. If no names are provided, all locations are returned.
+func filterLocations(azureLocations *azure.AzureLocationList, locations []string) *azure.AzureLocationList {
+	// If no locations are provided, return all locations
 	if len(locations) == 0 {
-		return azureLocations, nil
+		return azureLocations
 	}
 
-	// Filter the locations based on the locations provided in the --location flag
+	// Filter the locations based on the locations provided
 	// 1. Create a set of locations
 	locationSet := make(map[string]struct{})
 	for _, location := range locations {
@@ -45,16 +170,11 @@ func getLocations(cmd *cobra.Command, cred *azidentity.DefaultAzureCredential, c
 		}
 	}
 
-	return &azure.AzureLocationList{Value: filteredLocations}, nil
+	return &azure.AzureLocationList{Value: filteredLocations}
 }
 
-// This function is used to get all the locations from the Azure subscription
-func getAllLocationsFromSubscription(cred *azidentity.DefaultAzureCredential, ctx context.Context, subscriptionId string) (*azure.AzureLocationList, error) {
-	azureLocationLocator := azure.NewAzureLocationLocator(cred, ctx, subscriptionId)
-	azureLocations, err := azureLocationLocator.GetLocations()
-	if err != nil {
-		return nil, err
-	}
-
-	return azureLocations, nil
+// This function is used to get all the locations from the Azure subscription.
+// The locations are read once per session, however many verifications need them.
+func getAllLocationsFromSubscription(ctx context.Context, session *azure.Session) (*azure.AzureLocationList, error) {
+	return session.Locations(ctx)
 }
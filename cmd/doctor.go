@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// The statuses of the checks of the doctor command
+const (
+	doctorOk          = "ok"
+	doctorFailed      = "failed"
+	doctorUnavailable = "unavailable"
+	doctorSkipped     = "skipped"
+	doctorMissing     = "missing"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the authentication and the permissions needed to verify the services",
+	Long: `The doctor command diagnoses why the verifications fail to authenticate or to read the capabilities of the services:
+  the cloud of --cloud
+  the credentials tried by --auth-mode, and the one that authenticated
+  the tenant and the principal of the access token
+  the access to the subscription
+  the Microsoft.Authorization permissions of the principal for the read actions of each service check`,
+	Example: `  azure-resource-verifier doctor -s <subscription-id>
+  azure-resource-verifier doctor --service postgresql --service aks --auth-mode azcli`,
+
+	RunE: doctorCommand,
+}
+
+// doctorCheck is the result of a check of the doctor command
+type doctorCheck struct {
+	Check   string `json:"check" yaml:"check"`
+	Status  string `json:"status" yaml:"status"`
+	Details string `json:"details,omitempty" yaml:"details,omitempty"`
+
+	// The failure of the credentials of a chain is expected when another credential authenticates
+	optional bool
+}
+
+func doctorCommand(cmd *cobra.Command, _ []string) error {
+	// Silence usage so we don't print the usage when an error occurs
+	cmd.SilenceUsage = true
+
+	if err := viper.BindPFlags(cmd.Flags()); err != nil {
+		return fmt.Errorf("error binding flags: %s", err)
+	}
+
+	services, err := getDoctorServices(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cli.NewCommandContext()
+	defer cancel()
+
+	sessionOptions, err := cli.NewSessionOptions(ctx)
+	if err != nil {
+		return err
+	}
+	// The doctor calls the Azure APIs, the cached responses would hide their errors
+	sessionOptions.Cache = nil
+
+	checks := []*doctorCheck{{Check: "Cloud", Status: doctorOk, Details: azure.CloudEndpoint(sessionOptions.Cloud)}}
+
+	credentialOptions := cli.NewCredentialOptions()
+	credentialOptions.Cloud = sessionOptions.Cloud
+
+	cred, principal, credentialChecks := diagnoseCredentials(ctx, credentialOptions)
+	checks = append(checks, credentialChecks...)
+	if cred == nil {
+		checks = append(checks, &doctorCheck{Check: "Authentication", Status: doctorFailed, Details: "No credential authenticated"})
+		return renderDoctor(cmd, checks)
+	}
+
+	checks = append(checks,
+		&doctorCheck{Check: "Tenant", Status: doctorOk, Details: principal.TenantId},
+		&doctorCheck{Check: "Principal", Status: doctorOk, Details: fmt.Sprintf("%s (%s, object id %s)", principal.Name, principal.Type, principal.ObjectId)},
+	)
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		checks = append(checks, &doctorCheck{Check: "Subscription", Status: doctorFailed, Details: firstLine(err)})
+		return renderDoctor(cmd, checks)
+	}
+
+	session := azure.NewSession(cred, subscriptionId, sessionOptions)
+
+	subscription, err := azure.NewAzureSubscriptionLocator(session).GetSubscription(ctx, subscriptionId)
+	if err != nil {
+		checks = append(checks, &doctorCheck{Check: "Subscription", Status: doctorFailed, Details: firstLine(err)})
+		return renderDoctor(cmd, checks)
+	}
+
+	subscriptionCheck := &doctorCheck{Check: "Subscription", Status: doctorOk, Details: fmt.Sprintf("%s (%s), %s", subscription.Name(), subscription.Id, subscription.State)}
+	if !subscription.IsEnabled() {
+		subscriptionCheck.Status = doctorFailed
+	}
+	checks = append(checks, subscriptionCheck)
+
+	checks = append(checks, diagnosePermissions(ctx, session, services)...)
+
+	return renderDoctor(cmd, checks)
+}
+
+// This function is used to get the services to diagnose the permissions of, with the --service flag.
+// All the services are diagnosed by default.
+func getDoctorServices(cmd *cobra.Command) ([]string, error) {
+	services, err := cmd.Flags().GetStringArray("service")
+	if err != nil {
+		return nil, cli.CreateAzrErr("Error parsing service flag", err)
+	}
+
+	if len(services) == 0 {
+		return azure.Services(), nil
+	}
+
+	for _, service := range services {
+		if _, err := azure.RequiredReadActions(service); err != nil {
+			return nil, cli.CreateAzrErr("Error parsing service flag", fmt.Errorf("%w, expected one of %s", err, strings.Join(azure.Services(), ", ")))
+		}
+	}
+	return services, nil
+}
+
+// This function is used to try the credentials of the auth mode in order, as DefaultAzureCredential does,
+// and to report why each credential failed. The first credential that gets a token is returned with its principal.
+func diagnoseCredentials(ctx context.Context, options cli.CredentialOptions) (azcore.TokenCredential, *azure.AzurePrincipal, []*doctorCheck) {
+	checks := []*doctorCheck{}
+
+	var cred azcore.TokenCredential
+	var principal *azure.AzurePrincipal
+	for _, chained := range cli.NewCredentialChain(options) {
+		check := &doctorCheck{Check: "Credential " + chained.Name, optional: true}
+		checks = append(checks, check)
+
+		if cred != nil {
+			check.Status = doctorSkipped
+			check.Details = "A previous credential authenticated"
+			continue
+		}
+
+		if chained.Err != nil {
+			check.Status = doctorUnavailable
+			check.Details = firstLine(chained.Err)
+			continue
+		}
+
+		p, err := getChainedPrincipal(ctx, chained, options)
+		if err != nil {
+			check.Status = doctorFailed
+			check.Details = firstLine(err)
+			continue
+		}
+
+		cred, principal = chained.Credential, p
+		check.Status = doctorOk
+		check.Details = "Authenticated"
+	}
+
+	return cred, principal, checks
+}
+
+// This function is used to get the principal of a credential of the chain. The token request is limited by the
+// timeout of the credential, if any, e.g. the short probe of the managed identity endpoint.
+func getChainedPrincipal(ctx context.Context, chained *cli.ChainedCredential, options cli.CredentialOptions) (*azure.AzurePrincipal, error) {
+	if chained.Timeout <= 0 {
+		return azure.GetPrincipal(ctx, chained.Credential, options.Cloud)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, chained.Timeout)
+	defer cancel()
+
+	p, err := azure.GetPrincipal(probeCtx, chained.Credential, options.Cloud)
+	if err != nil && ctx.Err() == nil && probeCtx.Err() != nil {
+		return nil, fmt.Errorf("no token within %s, the endpoint is not reachable", chained.Timeout)
+	}
+	return p, err
+}
+
+// This function is used to verify the principal has the read actions of the service checks in the subscription.
+func diagnosePermissions(ctx context.Context, session *azure.Session, services []string) []*doctorCheck {
+	permissions, err := azure.NewAzureAuthorization(session).GetPermissions(ctx)
+	if err != nil {
+		return []*doctorCheck{{Check: "Permissions", Status: doctorFailed, Details: firstLine(err)}}
+	}
+
+	// The services needing each action, in the order of the services
+	actions := []string{}
+	actionServices := map[string][]string{}
+	for _, service := range services {
+		required, err := azure.RequiredReadActions(service)
+		if err != nil {
+			return []*doctorCheck{{Check: "Permissions", Status: doctorFailed, Details: err.Error()}}
+		}
+
+		for _, action := range required {
+			if _, ok := actionServices[action]; !ok {
+				actions = append(actions, action)
+			}
+			if !slices.Contains(actionServices[action], service) {
+				actionServices[action] = append(actionServices[action], service)
+			}
+		}
+	}
+
+	checks := []*doctorCheck{}
+	for _, action := range actions {
+		check := &doctorCheck{Check: action, Status: doctorOk, Details: "Needed by " + strings.Join(actionServices[action], ", ")}
+		if !permissions.IsAllowed(action) {
+			check.Status = doctorMissing
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// This function is used to render the checks, and to fail if a check failed or a permission is missing.
+func renderDoctor(cmd *cobra.Command, checks []*doctorCheck) error {
+	t := table.NewTable(table.Doctor)
+
+	problems := 0
+	for _, check := range checks {
+		t.AppendRow([]string{check.Check, check.Status, check.Details})
+		if !check.optional && (check.Status == doctorFailed || check.Status == doctorMissing) {
+			problems++
+		}
+	}
+
+	t.SetData(checks)
+
+	if err := renderTable(cmd, t); err != nil {
+		return err
+	}
+
+	if problems > 0 {
+		return cli.CreateAzrErr("The doctor found problems", fmt.Errorf("%d checks failed", problems))
+	}
+
+	cmd.PrintErrln("No problems found")
+	return nil
+}
+
+// This function is used to shorten the errors of the credentials and of the Azure APIs, which span several lines.
+func firstLine(err error) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(err.Error()), "\n")
+	return line
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+	doctorCmd.Flags().StringArray("service", []string{}, fmt.Sprintf("The service to verify the permissions of (%s). Can be specified multiple times. Defaults to all services", strings.Join(azure.Services(), ", ")))
+}
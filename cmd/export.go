@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/export"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var policyEffectChoice = cli.CliChoice{
+	Name:        "policy-effect",
+	Description: "The effect of the exported allowed locations policy (deny or audit)",
+	Default:     export.DenyEffect,
+	Choices:     export.PolicyEffects,
+}
+
+// This function is used to validate the --export-policy and --policy-effect flags before the verification runs,
+// so that a typo doesn't fail the export after the whole verification.
+func validateExportPolicyFlags(cmd *cobra.Command, _ []string) error {
+	file, err := cmd.Flags().GetString("export-policy")
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		return nil
+	}
+
+	if _, err := export.PolicyFormatFromFile(file); err != nil {
+		return cli.CreateAzrErr("Error parsing export-policy flag", err)
+	}
+
+	effect, err := cmd.Flags().GetString(policyEffectChoice.Name)
+	if err != nil {
+		return err
+	}
+	if valid := policyEffectChoice.IsValidChoice(effect); !valid {
+		return cli.CreateAzrErr(fmt.Sprintf("Invalid policy effect choice: %s", effect), nil)
+	}
+
+	return nil
+}
+
+// This function is used to write the locations to the file given with the --export-policy flag as an
+// Azure Policy allowed locations definition. The format is given by the extension of the file.
+// No file is written when no location supports the services, nor when the verification was cancelled, since
+// the locations that were not verified would be left out of the policy.
+func exportPolicy(cmd *cobra.Command, ctx context.Context, locations *azure.AzureLocationList) error {
+	file := viper.GetString("export-policy")
+	if file == "" {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		cmd.PrintErrf("The verification was cancelled, no policy file was written to %s\n", file)
+		return nil
+	}
+
+	// The flags are validated by validateExportPolicyFlags before the verification
+	format, err := export.PolicyFormatFromFile(file)
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing export-policy flag", err)
+	}
+	effect := viper.GetString(policyEffectChoice.Name)
+
+	names := make([]string, 0, len(locations.Value))
+	for _, location := range locations.Value {
+		names = append(names, location.Name)
+	}
+
+	policy, err := export.NewAllowedLocationsPolicy(names, effect)
+	if err != nil {
+		return cli.CreateAzrErr("Error creating the policy, no policy file was written", err)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return cli.CreateAzrErr("Error creating the policy file", err)
+	}
+
+	if err := policy.Write(f, format); err != nil {
+		f.Close()
+		return cli.CreateAzrErr("Error writing the policy file", err)
+	}
+	if err := f.Close(); err != nil {
+		return cli.CreateAzrErr("Error writing the policy file", err)
+	}
+
+	cmd.PrintErrf("Allowed locations policy written to %s\n", file)
+
+	return nil
+}
@@ -2,15 +2,12 @@ package cmd
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"os"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
 	"github.com/nickdala/azure-resource-verifier/internal/cli"
 	"github.com/nickdala/azure-resource-verifier/internal/table"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // locationlistCmd represents the locationlist command
@@ -21,38 +18,35 @@ var locationlistCmd = &cobra.Command{
 	RunE:  cli.AzureClientWrapRunE(listLocationsCommand),
 }
 
-func listLocationsCommand(cmd *cobra.Command, _ []string, cred *azidentity.DefaultAzureCredential, ctx context.Context) error {
-	fmt.Println("Listing all locations in the Azure subscription")
+func listLocationsCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("Listing all locations in the Azure subscription")
 
-	subscriptionId := viper.GetString("subscription-id")
-	log.Printf("subscription-id: %s", subscriptionId)
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
 
-	locations, err := getAllLocationsFromSubscription(cred, ctx, subscriptionId)
+	locations, err := getAllLocationsFromSubscription(ctx, session)
 	if err != nil {
 		return cli.CreateAzrErr("Error getting locations", err)
 	}
 
-	table := table.NewTable(table.Locations)
+	t := table.NewTable(table.Locations)
 
 	for _, location := range locations.Value {
-		table.AppendRow([]string{location.Name, location.DisplayName})
+		t.AppendRow([]string{location.Name, location.DisplayName})
 	}
 
-	table.Render()
-
-	return nil
+	return renderTable(cmd, t)
 
 }
 
 func init() {
 	rootCmd.AddCommand(locationlistCmd)
 
-	locationlistCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id")
-	// Required
-	if err := locationlistCmd.MarkFlagRequired("subscription-id"); err != nil {
-		locationlistCmd.Printf("Error marking flag required: %s", err)
-		os.Exit(1)
-	}
+	locationlistCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
 
 	// Here you will define your flags and configuration settings.
 
@@ -2,6 +2,7 @@ package appservice
 
 import (
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -27,7 +28,8 @@ type model struct {
 
 func ShowAppServiceModalAndGetChoices() (int, error) {
 
-	p := tea.NewProgram(initialModel())
+	// Render the modal to stderr so that stdout only contains the command output
+	p := tea.NewProgram(initialModel(), tea.WithOutput(os.Stderr))
 	m, err := p.Run()
 	if err != nil {
 		return -1, fmt.Errorf("error running the modal: %v", err)
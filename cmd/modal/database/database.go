@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -10,6 +11,8 @@ const (
 	REDIS = iota
 	POSTGRESQL
 	POSTGRESQL_HA
+	MYSQL
+	MYSQL_HA
 )
 
 type databaseChoice struct {
@@ -26,7 +29,8 @@ type model struct {
 func ShowDatabaseModalAndGetChoices() ([]int, error) {
 	databaseChoices := []int{}
 
-	p := tea.NewProgram(initialModel())
+	// Render the modal to stderr so that stdout only contains the command output
+	p := tea.NewProgram(initialModel(), tea.WithOutput(os.Stderr))
 	m, err := p.Run()
 	if err != nil {
 		return databaseChoices, fmt.Errorf("error running the modal: %v", err)
@@ -50,6 +54,8 @@ func initialModel() model {
 			{REDIS, "Azure Cache for Redis"},
 			{POSTGRESQL, "Azure PostgreSQL Flexible Server"},
 			{POSTGRESQL_HA, "Azure PostgreSQL Flexible Server with HA"},
+			{MYSQL, "Azure Database for MySQL Flexible Server"},
+			{MYSQL_HA, "Azure Database for MySQL Flexible Server with HA"},
 		},
 
 		// A map which indicates which choices are selected. The keys
@@ -101,6 +107,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					delete(m.selected, POSTGRESQL)
 				} else if m.cursor == POSTGRESQL { // If the user selects the PostgreSQL option, also deselect the PostgreSQL HA option
 					delete(m.selected, POSTGRESQL_HA)
+				} else if m.cursor == MYSQL_HA { // Same for the MySQL and MySQL HA options
+					delete(m.selected, MYSQL)
+				} else if m.cursor == MYSQL {
+					delete(m.selected, MYSQL_HA)
 				}
 			}
 		}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+)
+
+// mysqlCmd represents the mysql command
+var mysqlCmd = &cobra.Command{
+	Use:   "mysql",
+	Short: "Verify Azure Database for MySQL Flexible Server capabilities",
+	Long:  `The mysql command provides the means to verify Azure Database for MySQL Flexible Server capabilities.`,
+
+	RunE: cli.AzureClientWrapRunE(mysqlCommand),
+}
+
+func mysqlCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("mysql called")
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	locations, err := getLocations(cmd, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	azureMysql := azure.NewAzureMysqlFlexibleServer(session)
+
+	mysqlResults, err := azureMysql.GetMysqlLocations(ctx, locations)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting MySQL locations", err)
+	}
+
+	if err := verifyPolicies(cmd, ctx, session, mysqlResults); err != nil {
+		return err
+	}
+
+	if err := verifyRegistrations(cmd, ctx, session, mysqlResults); err != nil {
+		return err
+	}
+
+	return renderTable(cmd, newResultsTable(table.MySqlService, mysqlResults))
+}
+
+func init() {
+	rootCmd.AddCommand(mysqlCmd)
+
+	mysqlCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+
+	mysqlCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
+	mysqlCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
+	mysqlCmd.MarkFlagsOneRequired("location", "all-locations")
+	mysqlCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	addVerificationFlags(mysqlCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// quickstartCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// quickstartCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// The step of the verification skipped when the Azure Policy assignments aren't read
+const policyEvaluationStep = "the policy assignments were evaluated"
+
+// The Azure Policy assignments the locations of the results are verified against, and the scope the services
+// are deployed to, the subscription or the resource group
+type policyAssignments struct {
+	assignments []*azure.PolicyAssignment
+	scope       string
+	// The assignments were not read because the verification was cancelled
+	cancelled bool
+}
+
+// This function is used to mark the locations of the results that are denied by the assignments as unsupported.
+// The virtual machine sizes are the sizes the service is deployed with, if any. The deployable locations are
+// marked as unknown when the assignments were not read, they are not verified against them.
+func (p *policyAssignments) apply(ctx context.Context, results *azure.VerificationResultList, vmSizes ...string) {
+	if p.cancelled {
+		results.CancelDeployable(ctx, policyEvaluationStep)
+		return
+	}
+
+	results.ApplyPolicies(p.assignments, p.scope, vmSizes...)
+}
+
+// This function is used to mark the locations of the results that are denied by the Azure Policy assignments
+// as unsupported. The virtual machine sizes are the sizes the service is deployed with, if any.
+func verifyPolicies(cmd *cobra.Command, ctx context.Context, session *azure.Session, results *azure.VerificationResultList, vmSizes ...string) error {
+	policies, err := getPolicyAssignments(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	policies.apply(ctx, results, vmSizes...)
+
+	return nil
+}
+
+// This function is used to get the Azure Policy assignments in effect at the scope given with the
+// --resource-group or --management-group flags, or at the subscription.
+func getPolicyAssignments(ctx context.Context, session *azure.Session) (*policyAssignments, error) {
+	azurePolicy := azure.NewAzurePolicy(session)
+	policyScope := azurePolicy.PolicyScope(viper.GetString("resource-group"), viper.GetString("management-group"))
+	// The excluded scopes of the assignments are evaluated against the subscription or the resource group,
+	// also when the assignments are read at the scope of a management group
+	scope := azurePolicy.PolicyScope(viper.GetString("resource-group"), "")
+
+	assignments, err := azurePolicy.GetPolicyAssignments(ctx, policyScope)
+	if err != nil && ctx.Err() != nil {
+		// The partial results of a cancelled verification are rendered without the assignments
+		log.Printf("Skipping the policy assignments: %s", context.Cause(ctx))
+		return &policyAssignments{scope: scope, cancelled: true}, nil
+	}
+	if err != nil {
+		return nil, cli.CreateAzrErr("Error getting the policy assignments", err)
+	}
+
+	return &policyAssignments{assignments: assignments, scope: scope}, nil
+}
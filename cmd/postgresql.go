@@ -2,16 +2,12 @@ package cmd
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"os"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/nickdala/azure-resource-verifier/internal/azure"
 	"github.com/nickdala/azure-resource-verifier/internal/cli"
 	"github.com/nickdala/azure-resource-verifier/internal/table"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // postgresqlCmd represents the postgresql command
@@ -23,60 +19,55 @@ var postgresqlCmd = &cobra.Command{
 	RunE: cli.AzureClientWrapRunE(postgresqlCommand),
 }
 
-func postgresqlCommand(cmd *cobra.Command, _ []string, cred *azidentity.DefaultAzureCredential, ctx context.Context) error {
-	fmt.Println("postgresql called")
+func postgresqlCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("postgresql called")
 
-	subscriptionId := viper.GetString("subscription-id")
-	log.Printf("subscription-id: %s", subscriptionId)
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
 
-	locations, err := getLocations(cmd, cred, ctx, subscriptionId)
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	locations, err := getLocations(cmd, ctx, session)
 	if err != nil {
 		return cli.CreateAzrErr("Error parsing location flag", err)
 	}
 
-	var data [][]string
-
-	azurePostgresql := azure.NewAzurePostgresqlFlexibleServer(cred, ctx, subscriptionId)
+	azurePostgresql := azure.NewAzurePostgresqlFlexibleServer(session)
 
-	postgresLocations, postgresqlHaLocations, postgresqlNonDeployable, err := azurePostgresql.GetPostgresqlLocations(locations)
+	postgresqlResults, err := azurePostgresql.GetPostgresqlLocations(ctx, locations)
 	if err != nil {
 		return cli.CreateAzrErr("Error getting PostgreSQL locations", err)
 	}
 
-	for _, location := range postgresLocations.Value {
-		data = append(data, []string{location.Name, location.DisplayName, "true", "false", ""})
+	if err := attachQuotas(ctx, session, azure.PostgresqlService, locations, postgresqlResults); err != nil {
+		return cli.CreateAzrErr("Error getting PostgreSQL quota usages", err)
 	}
 
-	for _, location := range postgresqlHaLocations.Value {
-		data = append(data, []string{location.Name, location.DisplayName, "true", "true", ""})
+	if err := verifyPolicies(cmd, ctx, session, postgresqlResults); err != nil {
+		return err
 	}
 
-	for _, location := range postgresqlNonDeployable.Value {
-		data = append(data, []string{location.Name, location.DisplayName, "false", "false", location.Reason})
+	if err := verifyRegistrations(cmd, ctx, session, postgresqlResults); err != nil {
+		return err
 	}
 
-	table := table.NewTable(table.PostgreSqlService)
-	table.AppendBulk(data)
-	table.Render()
-
-	return nil
+	return renderTable(cmd, newResultsTable(table.PostgreSqlService, postgresqlResults))
 }
 
 func init() {
 	rootCmd.AddCommand(postgresqlCmd)
 
-	postgresqlCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id")
-	// Required
-	if err := postgresqlCmd.MarkFlagRequired("subscription-id"); err != nil {
-		postgresqlCmd.Printf("Error marking flag required: %s", err)
-		os.Exit(1)
-	}
+	postgresqlCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
 
 	postgresqlCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
 	postgresqlCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
 	postgresqlCmd.MarkFlagsOneRequired("location", "all-locations")
 	postgresqlCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
 
+	addVerificationFlags(postgresqlCmd)
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
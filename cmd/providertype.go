@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+)
+
+// providerTypeCmd represents the provider-type command
+var providerTypeCmd = &cobra.Command{
+	Use:   "provider-type <Namespace/resourceType>",
+	Short: "Verify any Azure resource type can be deployed to a location",
+	Long: `The provider-type command provides the means to verify a resource type of any resource provider,
+e.g. Microsoft.App/managedEnvironments, is offered in a location.
+The availability zones and the API versions of the resource type are reported per location.
+The latest stable API version is shown in the table, use --output json or yaml for all the API versions.`,
+	Example: `  azure-resource-verifier provider-type Microsoft.App/managedEnvironments -s <subscription-id> -l eastus2`,
+	Args:    cobra.ExactArgs(1),
+
+	RunE: cli.AzureClientWrapRunE(providerTypeCommand),
+}
+
+func providerTypeCommand(cmd *cobra.Command, args []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("provider-type called")
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	resourceType := args[0]
+	if _, _, err := azure.ParseResourceType(resourceType); err != nil {
+		return cli.CreateAzrErr("Error parsing the resource type", err)
+	}
+
+	locations, err := getLocations(cmd, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	azureResourceProvider := azure.NewAzureResourceProvider(session)
+
+	resourceTypeResults, err := azureResourceProvider.GetResourceTypeLocations(ctx, locations, resourceType, "")
+	if err != nil {
+		return cli.CreateAzrErr("Error getting resource type locations", err)
+	}
+
+	if err := verifyPolicies(cmd, ctx, session, resourceTypeResults); err != nil {
+		return err
+	}
+
+	if err := verifyRegistrations(cmd, ctx, session, resourceTypeResults); err != nil {
+		return err
+	}
+
+	return renderTable(cmd, newResultsTable(table.ResourceType, resourceTypeResults))
+}
+
+func init() {
+	rootCmd.AddCommand(providerTypeCmd)
+
+	providerTypeCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+
+	providerTypeCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
+	providerTypeCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
+	providerTypeCmd.MarkFlagsOneRequired("location", "all-locations")
+	providerTypeCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	addVerificationFlags(providerTypeCmd)
+}
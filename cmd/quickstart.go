@@ -3,17 +3,14 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/nickdala/azure-resource-verifier/cmd/modal/appservice"
 	"github.com/nickdala/azure-resource-verifier/cmd/modal/database"
 	"github.com/nickdala/azure-resource-verifier/internal/azure"
 	"github.com/nickdala/azure-resource-verifier/internal/cli"
 	"github.com/nickdala/azure-resource-verifier/internal/table"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // quickstartCmd represents the quickstart command
@@ -22,45 +19,60 @@ var quickstartCmd = &cobra.Command{
 	Short: "Quickstart Azure Resource Verifier",
 	Long:  `The quickstart command command provides a guided experience that shows the regions the Azure resources can be deployed.`,
 
-	RunE: cli.AzureClientWrapRunE(quickStartCommand),
+	PreRunE: validateExportPolicyFlags,
+	RunE:    cli.AzureClientWrapInteractiveRunE(quickStartCommand),
 }
 
-func quickStartCommand(cmd *cobra.Command, _ []string, cred *azidentity.DefaultAzureCredential, ctx context.Context) error {
-	fmt.Println("quickstart called")
+func quickStartCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("quickstart called")
 
-	subscriptionId := viper.GetString("subscription-id")
-	log.Printf("subscription-id: %s", subscriptionId)
-
-	azureLocations, err := getLocations(cmd, cred, ctx, subscriptionId)
+	subscriptionId, err := getSubscriptionId()
 	if err != nil {
-		return cli.CreateAzrErr("Error parsing location flag", err)
+		return err
 	}
 
 	databases, _ := database.ShowDatabaseModalAndGetChoices()
 	appService, _ := appservice.ShowAppServiceModalAndGetChoices()
 
+	// The time spent choosing in the modals doesn't count against the --timeout
+	ctx, cancel := cli.WithCommandTimeout(ctx)
+	defer cancel()
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	azureLocations, err := getLocations(cmd, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	// The locations denied by the Azure Policy assignments are left out of every step
+	policies, err := getPolicyAssignments(ctx, session)
+	if err != nil {
+		return err
+	}
+
 	switch appService {
 	case appservice.APP_SERVICE_LINUX_CODE:
-		println("Selected: Azure App Service - Linux Code")
-		azureLocations, err = getLocationsForAppService(azureLocations, cred, ctx, subscriptionId, azure.Linux, azure.Code)
+		cmd.PrintErrln("Selected: Azure App Service - Linux Code")
+		azureLocations, err = getLocationsForAppService(cmd, ctx, session, policies, azureLocations, azure.Linux, azure.Code)
 		if err != nil {
 			return cli.CreateAzrErr("Error getting App Service locations", err)
 		}
 	case appservice.APP_SERVICE_LINUX_CONTAINER:
-		println("Selected: Azure App Service - Linux Container")
-		azureLocations, err = getLocationsForAppService(azureLocations, cred, ctx, subscriptionId, azure.Linux, azure.Container)
+		cmd.PrintErrln("Selected: Azure App Service - Linux Container")
+		azureLocations, err = getLocationsForAppService(cmd, ctx, session, policies, azureLocations, azure.Linux, azure.Container)
 		if err != nil {
 			return cli.CreateAzrErr("Error getting App Service locations", err)
 		}
 	case appservice.APP_SERVICE_WINDOWS_CODE:
-		println("Selected: Azure App Service - Windows Code")
-		azureLocations, err = getLocationsForAppService(azureLocations, cred, ctx, subscriptionId, azure.Windows, azure.Code)
+		cmd.PrintErrln("Selected: Azure App Service - Windows Code")
+		azureLocations, err = getLocationsForAppService(cmd, ctx, session, policies, azureLocations, azure.Windows, azure.Code)
 		if err != nil {
 			return cli.CreateAzrErr("Error getting App Service locations", err)
 		}
 	case appservice.APP_SERVICE_WINDOWS_CONTAINER:
-		println("Selected: Azure App Service - Windows Container")
-		azureLocations, err = getLocationsForAppService(azureLocations, cred, ctx, subscriptionId, azure.Windows, azure.Container)
+		cmd.PrintErrln("Selected: Azure App Service - Windows Container")
+		azureLocations, err = getLocationsForAppService(cmd, ctx, session, policies, azureLocations, azure.Windows, azure.Container)
 		if err != nil {
 			return cli.CreateAzrErr("Error getting App Service locations", err)
 		}
@@ -69,89 +81,146 @@ func quickStartCommand(cmd *cobra.Command, _ []string, cred *azidentity.DefaultA
 	for _, db := range databases {
 		switch db {
 		case database.REDIS:
-			println("Selected: Azure Cache for Redis")
-			azureLocations, err = getLocationsForRedis(azureLocations, cred, ctx, subscriptionId)
+			cmd.PrintErrln("Selected: Azure Cache for Redis")
+			azureLocations, err = getLocationsForRedis(cmd, ctx, session, policies, azureLocations)
 			if err != nil {
 				return cli.CreateAzrErr("Error getting Redis locations", err)
 			}
 		case database.POSTGRESQL:
-			println("Selected: Azure PostgreSQL Flexible Server")
-			azureLocations, err = getPostgresLocations(subscriptionId, cred, ctx, azureLocations, false)
+			cmd.PrintErrln("Selected: Azure PostgreSQL Flexible Server")
+			azureLocations, err = getPostgresLocations(cmd, ctx, session, policies, azureLocations, false)
 			if err != nil {
 				return cli.CreateAzrErr("Error getting PostgreSQL locations", err)
 			}
 		case database.POSTGRESQL_HA:
-			println("Selected: Azure PostgreSQL Flexible Server with HA")
-			azureLocations, err = getPostgresLocations(subscriptionId, cred, ctx, azureLocations, true)
+			cmd.PrintErrln("Selected: Azure PostgreSQL Flexible Server with HA")
+			azureLocations, err = getPostgresLocations(cmd, ctx, session, policies, azureLocations, true)
 			if err != nil {
 				return cli.CreateAzrErr("Error getting PostgreSQL HA locations", err)
 			}
+		case database.MYSQL:
+			cmd.PrintErrln("Selected: Azure Database for MySQL Flexible Server")
+			azureLocations, err = getMysqlLocations(cmd, ctx, session, policies, azureLocations, false)
+			if err != nil {
+				return cli.CreateAzrErr("Error getting MySQL locations", err)
+			}
+		case database.MYSQL_HA:
+			cmd.PrintErrln("Selected: Azure Database for MySQL Flexible Server with HA")
+			azureLocations, err = getMysqlLocations(cmd, ctx, session, policies, azureLocations, true)
+			if err != nil {
+				return cli.CreateAzrErr("Error getting MySQL HA locations", err)
+			}
 		}
 	}
 
+	if err := exportPolicy(cmd, ctx, azureLocations); err != nil {
+		return err
+	}
+
+	// The locations that were not verified before the cancellation are left out, the list isn't complete
+	if ctx.Err() != nil {
+		cmd.PrintErrf("The verification was cancelled (%s), the list is partial: the locations that were not verified are left out\n", context.Cause(ctx))
+	}
+
 	var data [][]string
 	for _, location := range azureLocations.Value {
 		data = append(data, []string{location.Name, location.DisplayName})
 	}
 
-	table := table.NewTable(table.Locations)
-	table.AppendBulk(data)
-	table.Render()
+	t := table.NewTable(table.Locations)
+	t.AppendBulk(data)
+
+	if err := renderTable(cmd, t); err != nil {
+		return err
+	}
 
+	// The wrapper only sees the cancellation by a signal, the command fails here when the --timeout elapses
+	if ctx.Err() != nil {
+		return cli.CancelledErr(ctx)
+	}
 	return nil
 }
 
-func getLocationsForAppService(locations *azure.AzureLocationList, cred *azidentity.DefaultAzureCredential, ctx context.Context, subscriptionId string, os azure.AppServiceOS, publishType azure.AppServicePublishType) (*azure.AzureLocationList, error) {
-	azureAppService := azure.NewAzureAppService(cred, ctx, subscriptionId)
-	appServiceLocations, err := azureAppService.GetAppServiceLocations(locations, os, publishType)
+// This function is used to get the locations of the results of a quickstart step the service can be deployed to.
+// The locations denied by the Azure Policy assignments, and the locations of the services whose resource providers
+// aren't registered, are left out.
+func deployableLocations(cmd *cobra.Command, ctx context.Context, session *azure.Session, policies *policyAssignments, results *azure.VerificationResultList, features ...string) (*azure.AzureLocationList, error) {
+	policies.apply(ctx, results)
+
+	if err := verifyRegistrations(cmd, ctx, session, results); err != nil {
+		return nil, err
+	}
+
+	return results.DeployableLocations(features...), nil
+}
+
+func getLocationsForAppService(cmd *cobra.Command, ctx context.Context, session *azure.Session, policies *policyAssignments, locations *azure.AzureLocationList, os azure.AppServiceOS, publishType azure.AppServicePublishType) (*azure.AzureLocationList, error) {
+	azureAppService := azure.NewAzureAppService(session)
+	appServiceResults, err := azureAppService.GetAppServiceLocations(ctx, locations, os, publishType, "")
 	if err != nil {
 		return nil, fmt.Errorf("error getting App Service locations %w", err)
 	}
 
-	return locations.Intersection(appServiceLocations), nil
+	return deployableLocations(cmd, ctx, session, policies, appServiceResults)
 }
 
-func getLocationsForRedis(locations *azure.AzureLocationList, cred *azidentity.DefaultAzureCredential, ctx context.Context, subscriptionId string) (*azure.AzureLocationList, error) {
-	redisCache := azure.NewAzureRedisCache(cred, ctx, subscriptionId)
-	redisLocations, err := redisCache.GetRedisLocations()
+func getLocationsForRedis(cmd *cobra.Command, ctx context.Context, session *azure.Session, policies *policyAssignments, locations *azure.AzureLocationList) (*azure.AzureLocationList, error) {
+	redisCache := azure.NewAzureRedisCache(session)
+	redisResults, err := redisCache.GetRedisLocations(ctx, locations)
 	if err != nil {
 		return nil, fmt.Errorf("error getting Redis locations %w", err)
 	}
 
-	return locations.Intersection(redisLocations), nil
+	return deployableLocations(cmd, ctx, session, policies, redisResults)
 }
 
-func getPostgresLocations(subscriptionId string, cred *azidentity.DefaultAzureCredential, ctx context.Context, locations *azure.AzureLocationList, haEnabled bool) (*azure.AzureLocationList, error) {
+func getPostgresLocations(cmd *cobra.Command, ctx context.Context, session *azure.Session, policies *policyAssignments, locations *azure.AzureLocationList, haEnabled bool) (*azure.AzureLocationList, error) {
 
-	azurePostgresql := azure.NewAzurePostgresqlFlexibleServer(cred, ctx, subscriptionId)
+	azurePostgresql := azure.NewAzurePostgresqlFlexibleServer(session)
 
-	postgresLocations, postgresqlHaLocations, _, err := azurePostgresql.GetPostgresqlLocations(locations)
+	postgresqlResults, err := azurePostgresql.GetPostgresqlLocations(ctx, locations)
 	if err != nil {
 		return nil, fmt.Errorf("error getting PostgreSQL locations %w", err)
 	}
 
 	if haEnabled {
-		return locations.Intersection((*azure.AzureLocationList)(postgresqlHaLocations)), nil
+		return deployableLocations(cmd, ctx, session, policies, postgresqlResults, azure.HighAvailabilityFeature)
+	} else {
+		return deployableLocations(cmd, ctx, session, policies, postgresqlResults)
+	}
+}
+
+func getMysqlLocations(cmd *cobra.Command, ctx context.Context, session *azure.Session, policies *policyAssignments, locations *azure.AzureLocationList, haEnabled bool) (*azure.AzureLocationList, error) {
+
+	azureMysql := azure.NewAzureMysqlFlexibleServer(session)
+
+	mysqlResults, err := azureMysql.GetMysqlLocations(ctx, locations)
+	if err != nil {
+		return nil, fmt.Errorf("error getting MySQL locations %w", err)
+	}
+
+	if haEnabled {
+		return deployableLocations(cmd, ctx, session, policies, mysqlResults, azure.HighAvailabilityFeature)
 	} else {
-		return locations.Intersection((*azure.AzureLocationList)(postgresLocations)), nil
+		return deployableLocations(cmd, ctx, session, policies, mysqlResults)
 	}
 }
 
 func init() {
 	rootCmd.AddCommand(quickstartCmd)
 
-	quickstartCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id")
-	// Required
-	if err := quickstartCmd.MarkFlagRequired("subscription-id"); err != nil {
-		quickstartCmd.Printf("Error marking flag required: %s", err)
-		os.Exit(1)
-	}
+	quickstartCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
 
 	quickstartCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
 	quickstartCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
 	quickstartCmd.MarkFlagsOneRequired("location", "all-locations")
 	quickstartCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
 
+	addVerificationFlags(quickstartCmd)
+
+	quickstartCmd.Flags().String("export-policy", "", "The file to export the selected locations to as an Azure Policy allowed locations definition (.json, .bicep or .tf)")
+	quickstartCmd.Flags().String(policyEffectChoice.Name, policyEffectChoice.Default, policyEffectChoice.Description)
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
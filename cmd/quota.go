@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+)
+
+// quotaCmd represents the quota command
+var quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "List the quota usages of the Azure subscription in a location",
+	Long: `The quota command lists the compute, network and PostgreSQL flexible server quota usages of the subscription
+per location, with the remaining headroom of each quota.`,
+	Example: `  azure-resource-verifier quota -s <subscription-id> -l eastus2 --provider compute`,
+
+	RunE: cli.AzureClientWrapRunE(quotaCommand),
+}
+
+func quotaCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("quota called")
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	providers, err := cmd.Flags().GetStringArray("provider")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing provider flag", err)
+	}
+
+	locations, err := getLocations(cmd, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	azureQuota := azure.NewAzureQuota(session)
+
+	usages, errs, err := azureQuota.GetQuotaUsages(ctx, locations, providers)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting quota usages", err)
+	}
+
+	// The locations whose usages can't be read are reported on stderr, the rows of the table are the usages read
+	for _, location := range locations.Value {
+		if err, ok := errs[location.Name]; ok {
+			cmd.PrintErrf("The quota usages of %s couldn't be read: %s\n", location.Name, err)
+		}
+	}
+
+	t := table.NewTable(table.Quota)
+	data := []*azure.QuotaUsage{}
+
+	for _, location := range locations.Value {
+		for _, usage := range usages[location.Name] {
+			t.AppendRow([]string{location.Name, usage.Provider, usage.DisplayName, strconv.FormatInt(usage.Current, 10), strconv.FormatInt(usage.Limit, 10), strconv.FormatInt(usage.Remaining(), 10)})
+			data = append(data, usage)
+		}
+	}
+
+	t.SetData(data)
+
+	return renderTable(cmd, t)
+}
+
+func init() {
+	rootCmd.AddCommand(quotaCmd)
+
+	quotaCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+
+	quotaCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
+	quotaCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
+	quotaCmd.MarkFlagsOneRequired("location", "all-locations")
+	quotaCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	quotaCmd.Flags().StringArray("provider", azure.QuotaProviders, fmt.Sprintf("The quota provider (%s). Can be specified multiple times", strings.Join(azure.QuotaProviders, ", ")))
+}
@@ -2,16 +2,12 @@ package cmd
 
 import (
 	"context"
-	"fmt"
-	"log"
-	"os"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/nickdala/azure-resource-verifier/internal/azure"
 	"github.com/nickdala/azure-resource-verifier/internal/cli"
 	"github.com/nickdala/azure-resource-verifier/internal/table"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // redisCmd represents the redis command
@@ -23,58 +19,50 @@ var redisCmd = &cobra.Command{
 	RunE: cli.AzureClientWrapRunE(redisCommand),
 }
 
-func redisCommand(cmd *cobra.Command, _ []string, cred *azidentity.DefaultAzureCredential, ctx context.Context) error {
-	fmt.Println("redis called")
+func redisCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("redis called")
 
-	subscriptionId := viper.GetString("subscription-id")
-	log.Printf("subscription-id: %s", subscriptionId)
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
 
-	azureLocations, err := getLocations(cmd, cred, ctx, subscriptionId)
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	azureLocations, err := getLocations(cmd, ctx, session)
 	if err != nil {
 		return cli.CreateAzrErr("Error parsing location flag", err)
 	}
 
-	redisCache := azure.NewAzureRedisCache(cred, ctx, subscriptionId)
-	redisLocations, err := redisCache.GetRedisLocations()
+	redisCache := azure.NewAzureRedisCache(session)
+	redisResults, err := redisCache.GetRedisLocations(ctx, azureLocations)
 	if err != nil {
 		return cli.CreateAzrErr("Error getting Redis locations", err)
 	}
 
-	deployableLocations := azureLocations.Intersection(redisLocations)
-	unsupportedRegions := azureLocations.Difference(redisLocations)
-
-	var data [][]string
-
-	for _, location := range deployableLocations.Value {
-		data = append(data, []string{location.Name, location.DisplayName, "true"})
+	if err := verifyPolicies(cmd, ctx, session, redisResults); err != nil {
+		return err
 	}
 
-	for _, location := range unsupportedRegions.Value {
-		data = append(data, []string{location.Name, location.DisplayName, "false"})
+	if err := verifyRegistrations(cmd, ctx, session, redisResults); err != nil {
+		return err
 	}
 
-	table := table.NewTable(table.RedisService)
-	table.AppendBulk(data)
-	table.Render()
-
-	return nil
+	return renderTable(cmd, newResultsTable(table.RedisService, redisResults))
 }
 
 func init() {
 	rootCmd.AddCommand(redisCmd)
 
-	redisCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id")
-	// Required
-	if err := redisCmd.MarkFlagRequired("subscription-id"); err != nil {
-		redisCmd.Printf("Error marking flag required: %s", err)
-		os.Exit(1)
-	}
+	redisCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
 
 	redisCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
 	redisCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
 	redisCmd.MarkFlagsOneRequired("location", "all-locations")
 	redisCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
 
+	addVerificationFlags(redisCmd)
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
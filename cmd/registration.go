@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"strings"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+// This function is used to verify the resource providers of the services are registered in the subscription.
+// The locations of the services whose providers aren't registered are marked as unsupported.
+// With the --fix flag, the missing registrations are printed and performed after a confirmation.
+func verifyRegistrations(cmd *cobra.Command, ctx context.Context, session *azure.Session, results ...*azure.VerificationResultList) error {
+	registrations, err := requireRegistrations(ctx, session, results...)
+	if err != nil {
+		return err
+	}
+
+	unregistered := registrations.Unregistered()
+	if len(unregistered) == 0 {
+		return nil
+	}
+
+	if fix, _ := cmd.Flags().GetBool("fix"); !fix {
+		cmd.PrintErrln("Some resource providers are not registered in the subscription. Use --fix to register them.")
+		return nil
+	}
+
+	cmd.PrintErrln("The following resource providers will be registered:")
+	for _, registration := range unregistered {
+		cmd.PrintErrf("  %s (%s)\n", registration.Namespace, registration.RegistrationState)
+	}
+
+	if !confirm(cmd, "Register the resource providers?") {
+		cmd.PrintErrln("No resource providers were registered")
+		return nil
+	}
+
+	azureResourceProvider := azure.NewAzureResourceProvider(session)
+	for _, registration := range unregistered {
+		registered, err := azureResourceProvider.RegisterProvider(ctx, registration.Namespace)
+		if err != nil {
+			return cli.CreateAzrErr("Error registering the resource provider", err)
+		}
+		cmd.PrintErrf("Registered %s: %s\n", registered.Namespace, registered.RegistrationState)
+	}
+
+	cmd.PrintErrln("The registration can take a few minutes. Run the verification again once it completes.")
+
+	return nil
+}
+
+// The step of the verification skipped when the registrations of the resource providers aren't read
+const registrationStep = "the resource provider registrations were verified"
+
+// This function is used to mark the locations of the services whose resource providers aren't registered
+// in the subscription as unsupported. The registrations of the resource providers are returned.
+// The deployable locations are marked as unknown when the registrations were not read.
+func requireRegistrations(ctx context.Context, session *azure.Session, results ...*azure.VerificationResultList) (azure.ProviderRegistrations, error) {
+	namespaces := []string{}
+	for _, result := range results {
+		namespaces = append(namespaces, result.Namespaces()...)
+	}
+
+	registrations, err := azure.NewAzureResourceProvider(session).GetProviderRegistrations(ctx, namespaces)
+	if err != nil && ctx.Err() != nil {
+		// The partial results of a cancelled verification are rendered without the registrations
+		log.Printf("Skipping the resource provider registrations: %s", context.Cause(ctx))
+		for _, result := range results {
+			result.CancelDeployable(ctx, registrationStep)
+		}
+		return azure.ProviderRegistrations{}, nil
+	}
+	if err != nil {
+		return nil, cli.CreateAzrErr("Error getting the resource provider registrations", err)
+	}
+
+	for _, result := range results {
+		result.RequireRegistration(registrations)
+	}
+
+	return registrations, nil
+}
+
+// This function is used to ask the user for a confirmation. Only y or yes confirms.
+func confirm(cmd *cobra.Command, question string) bool {
+	cmd.PrintErrf("%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && answer == "" {
+		cmd.PrintErrln()
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
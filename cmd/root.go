@@ -3,14 +3,25 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
 	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
 
+var outputFormatChoice = cli.CliChoice{
+	Name:        "output",
+	Description: "The output format (table, json, yaml or csv)",
+	Default:     string(table.TableFormat),
+	Choices:     table.Formats,
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "azure-resource-verifier",
@@ -20,6 +31,33 @@ For more information, please visit https://github.com/nickdala/azure-resource-ve
 
 	SilenceErrors: true, // don't print errors twice, we handle them in cli.ExitOnError
 
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		output, err := cmd.Flags().GetString(outputFormatChoice.Name)
+		if err != nil {
+			return err
+		}
+		if valid := outputFormatChoice.IsValidChoice(output); !valid {
+			return fmt.Errorf("invalid output format choice: %s", output)
+		}
+
+		cloudName, err := cmd.Flags().GetString(cli.CloudChoice.Name)
+		if err != nil {
+			return err
+		}
+		if valid := cli.CloudChoice.IsValidChoice(cloudName); !valid {
+			return fmt.Errorf("invalid cloud choice: %s", cloudName)
+		}
+
+		authMode, err := cmd.Flags().GetString(cli.AuthModeChoice.Name)
+		if err != nil {
+			return err
+		}
+		if valid := cli.AuthModeChoice.IsValidChoice(authMode); !valid {
+			return fmt.Errorf("invalid auth mode choice: %s", authMode)
+		}
+		return nil
+	},
+
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
@@ -42,6 +80,26 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
+	rootCmd.PersistentFlags().String(outputFormatChoice.Name, outputFormatChoice.Default, outputFormatChoice.Description)
+
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Whether to call the Azure APIs without reading or writing the cache of their responses")
+	rootCmd.PersistentFlags().Bool("refresh", false, "Whether to call the Azure APIs instead of reading the cache, the responses are cached again")
+	rootCmd.MarkFlagsMutuallyExclusive("no-cache", "refresh")
+
+	rootCmd.PersistentFlags().Duration("timeout", 0, "The maximum duration of the verification, e.g. 5m. The locations not verified in time are reported as unknown. Defaults to no timeout")
+	rootCmd.PersistentFlags().Duration("request-timeout", time.Minute, "The maximum duration of each request to the Azure Resource Manager API, e.g. 30s. A request that times out is retried")
+	rootCmd.PersistentFlags().Int("concurrency", azure.DefaultConcurrency, "The maximum number of requests in flight to verify the locations, across all the services and subscriptions")
+
+	rootCmd.PersistentFlags().String(cli.CloudChoice.Name, cli.CloudChoice.Default, cli.CloudChoice.Description)
+	rootCmd.PersistentFlags().String("arm-endpoint", "", "The endpoint of the Azure Resource Manager API of the custom cloud, e.g. https://management.local.azurestack.external")
+	rootCmd.PersistentFlags().String("arm-audience", "", "The audience of the Azure Resource Manager API of the custom cloud. Defaults to the audience of the metadata endpoint of the API")
+	rootCmd.PersistentFlags().String("authority-host", "", "The Microsoft Entra ID or AD FS authority host of the custom cloud. Defaults to the login endpoint of the metadata endpoint of the API")
+
+	rootCmd.PersistentFlags().String(cli.AuthModeChoice.Name, cli.AuthModeChoice.Default, cli.AuthModeChoice.Description)
+	rootCmd.PersistentFlags().String("tenant-id", "", "The Microsoft Entra tenant to authenticate in. Defaults to the tenant of the credential")
+	rootCmd.PersistentFlags().String("client-id", "", "The client id of the user-assigned managed identity, the workload identity or the application to authenticate with")
+	rootCmd.PersistentFlags().String("client-certificate", "", "The PEM or PKCS#12 certificate file of the client-certificate auth mode. The password is read from AZURE_CLIENT_CERTIFICATE_PASSWORD")
+
 	//rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $PWD/.azure-resource-verifier.yaml)")
 
 	// Cobra also supports local flags, which will only run
@@ -66,7 +124,16 @@ func initConfig() {
 		viper.SetConfigName(".azure-resource-verifier")
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	// Read in the environment variables of the flags, prefixed with ARV_ so that generic variables,
+	// e.g. TIMEOUT or ENVIRONMENT, don't set the flags. ARV_REQUEST_TIMEOUT sets --request-timeout.
+	viper.SetEnvPrefix("ARV")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
+
+	// The subscription is read from the environment variable of the Azure SDKs and tools
+	if err := viper.BindEnv("subscription-id", "AZURE_SUBSCRIPTION_ID"); err != nil {
+		fmt.Fprintln(os.Stderr, "Error binding the environment variables:", err)
+	}
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+)
+
+// sqlCmd represents the sql command
+var sqlCmd = &cobra.Command{
+	Use:   "sql",
+	Short: "Verify Azure SQL Database capabilities",
+	Long: `The sql command provides the means to verify Azure SQL Database capabilities.
+
+The command reports whether the location supports the edition and service objective,
+zone redundancy and serverless compute. Capabilities that are visible but restricted
+for the subscription are reported as degraded.`,
+	Example: `  azure-resource-verifier sql -s <subscription-id> -l eastus2 --edition GeneralPurpose --service-objective GP_S_Gen5 --zone-redundant
+  azure-resource-verifier sql -s <subscription-id> --all-locations --edition Hyperscale`,
+
+	RunE: cli.AzureClientWrapRunE(sqlCommand),
+}
+
+func sqlCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("sql called")
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	edition, err := cmd.Flags().GetString("edition")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing edition flag", err)
+	}
+	if edition != "" && !isSqlDatabaseEdition(edition) {
+		return cli.CreateAzrErr(fmt.Sprintf("Invalid edition: %s. Valid editions are %s", edition, strings.Join(azure.SqlDatabaseEditions, ", ")), nil)
+	}
+
+	serviceObjective, err := cmd.Flags().GetString("service-objective")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing service-objective flag", err)
+	}
+
+	locations, err := getLocations(cmd, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	azureSqlDatabase := azure.NewAzureSqlDatabase(session)
+
+	sqlResults, err := azureSqlDatabase.GetSqlDatabaseLocations(ctx, locations, edition, serviceObjective)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting SQL Database locations", err)
+	}
+
+	if zoneRedundant, _ := cmd.Flags().GetBool("zone-redundant"); zoneRedundant {
+		sqlResults.RequireFeature(azure.ZoneRedundancyFeature, azure.ZonesNotSupportedReason)
+	}
+
+	if err := verifyPolicies(cmd, ctx, session, sqlResults); err != nil {
+		return err
+	}
+
+	if err := verifyRegistrations(cmd, ctx, session, sqlResults); err != nil {
+		return err
+	}
+
+	return renderTable(cmd, newResultsTable(table.SqlDatabaseService, sqlResults))
+}
+
+func isSqlDatabaseEdition(edition string) bool {
+	for _, e := range azure.SqlDatabaseEditions {
+		if strings.EqualFold(e, edition) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(sqlCmd)
+
+	sqlCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+
+	sqlCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
+	sqlCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
+	sqlCmd.MarkFlagsOneRequired("location", "all-locations")
+	sqlCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	addVerificationFlags(sqlCmd)
+
+	sqlCmd.Flags().String("edition", "", "The edition of the database, e.g. GeneralPurpose, BusinessCritical or Hyperscale")
+	sqlCmd.Flags().String("service-objective", "", "The service objective or SKU of the database, e.g. GP_S_Gen5_2 or GP_S_Gen5")
+	sqlCmd.Flags().Bool("zone-redundant", false, "Whether the database must be zone redundant")
+}
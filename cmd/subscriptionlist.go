@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// subscriptionlistCmd represents the list-subscriptions command
+var subscriptionlistCmd = &cobra.Command{
+	Use:   "list-subscriptions",
+	Short: "Lists all subscriptions the credential can access",
+	Long: `The list-subscriptions command lists all subscriptions the credential can access, with their state and tenant.
+The default subscription is the subscription used by the commands without --subscription-id:
+the AZURE_SUBSCRIPTION_ID environment variable or the default subscription of the Azure CLI.`,
+	RunE: cli.AzureClientWrapRunE(listSubscriptionsCommand),
+}
+
+func listSubscriptionsCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("Listing all subscriptions the credential can access")
+
+	subscriptions, err := azure.NewAzureSubscriptionLocator(azure.NewSession(cred, "", options)).GetSubscriptions(ctx)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting subscriptions", err)
+	}
+
+	defaultSubscriptionId := getDefaultSubscriptionId()
+
+	t := table.NewTable(table.Subscriptions)
+
+	for _, subscription := range subscriptions {
+		isDefault := strings.EqualFold(subscription.Id, defaultSubscriptionId)
+		t.AppendRow([]string{subscription.Id, subscription.DisplayName, subscription.State, subscription.TenantId, strconv.FormatBool(isDefault)})
+	}
+
+	t.SetData(subscriptions)
+
+	return renderTable(cmd, t)
+}
+
+// This function is used to get the subscription the commands default to, without failing if there is none.
+func getDefaultSubscriptionId() string {
+	if subscriptionId := viper.GetString("subscription-id"); subscriptionId != "" {
+		return subscriptionId
+	}
+
+	subscription, err := azure.GetCliDefaultSubscription()
+	if err != nil {
+		log.Printf("No default subscription: %s", err)
+		return ""
+	}
+
+	return subscription.Id
+}
+
+func init() {
+	rootCmd.AddCommand(subscriptionlistCmd)
+}
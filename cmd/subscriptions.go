@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// The number of subscriptions verified at the same time by default
+const defaultSubscriptionConcurrency = 4
+
+// This function is used to tell whether several subscriptions are verified, i.e. the --all-subscriptions
+// or the --scan-management-group flag is given.
+func isSubscriptionScan() bool {
+	return viper.GetBool("all-subscriptions") || viper.GetString("scan-management-group") != ""
+}
+
+// This function is used to get the subscriptions to verify: the subscriptions of the management group given with
+// the --scan-management-group flag, or all the subscriptions the credential can access.
+func getScanSubscriptions(cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) ([]*azure.AzureSubscription, error) {
+	locator := azure.NewAzureSubscriptionLocator(azure.NewSession(cred, "", options))
+
+	if managementGroup := viper.GetString("scan-management-group"); managementGroup != "" {
+		return locator.GetManagementGroupSubscriptions(ctx, managementGroup)
+	}
+
+	subscriptions, err := locator.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The subscriptions that are not enabled, e.g. disabled or deleted, are not verified
+	enabled := []*azure.AzureSubscription{}
+	for _, subscription := range subscriptions {
+		if subscription.IsEnabled() {
+			enabled = append(enabled, subscription)
+		}
+	}
+	return enabled, nil
+}
+
+// This function is used to run the service checks in every subscription, a bounded number of subscriptions at a time.
+// The subscriptions that can't be verified, e.g. without access, are reported and skipped.
+// The results are rendered as a subscription x location x service report.
+func verifySubscriptions(cmd *cobra.Command, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context, checks []*serviceCheck, manifestLocations []string) error {
+	locationNames, err := cmd.Flags().GetStringArray("location")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	subscriptions, err := getScanSubscriptions(cred, options, ctx)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting the subscriptions", err)
+	}
+	cmd.PrintErrf("Verifying %d subscriptions\n", len(subscriptions))
+
+	concurrency := viper.GetInt("subscription-concurrency")
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([][]*azure.VerificationResultList, len(subscriptions))
+	locations := make([]*azure.AzureLocationList, len(subscriptions))
+	errs := make([]error, len(subscriptions))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for i, subscription := range subscriptions {
+		wg.Add(1)
+		go func(idx int, subscription *azure.AzureSubscription) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			log.Printf("Verifying subscription %s (%s)", subscription.Name(), subscription.Id)
+			results[idx], locations[idx], errs[idx] = verifySubscription(ctx, azure.NewSession(cred, subscription.Id, options), checks, locationNames, manifestLocations)
+		}(i, subscription)
+	}
+
+	wg.Wait()
+
+	allResults := &azure.VerificationResultList{}
+	var deployableLocations *azure.AzureLocationList
+	for i, subscription := range subscriptions {
+		if errs[i] != nil {
+			cmd.PrintErrf("Subscription %s could not be verified: %s\n", subscription.Name(), errs[i])
+			continue
+		}
+
+		// The deployable locations of the subscription are the locations supporting all the services
+		subscriptionLocations := locations[i]
+		for _, result := range results[i] {
+			subscriptionLocations = subscriptionLocations.Intersection(result.DeployableLocations())
+			for _, value := range result.Value {
+				value.Subscription = subscription
+			}
+			allResults.Append(result)
+		}
+
+		names := make([]string, 0, len(subscriptionLocations.Value))
+		for _, location := range subscriptionLocations.Value {
+			names = append(names, location.Name)
+		}
+		cmd.PrintErrf("Locations supporting all services in %s: %s\n", subscription.Name(), strings.Join(names, ", "))
+
+		if deployableLocations == nil {
+			deployableLocations = subscriptionLocations
+		} else {
+			deployableLocations = deployableLocations.Intersection(subscriptionLocations)
+		}
+	}
+
+	if deployableLocations != nil {
+		if err := exportPolicy(cmd, ctx, deployableLocations); err != nil {
+			return err
+		}
+	}
+
+	return renderTable(cmd, newResultsTable(table.MultipleSubscriptions, allResults))
+}
+
+// This function is used to run the service checks in the subscription of the session. The results are verified against the
+// Azure Policy assignments of the subscription and the resource provider registrations, without registering
+// the missing providers. The locations of the subscription the services are verified in are returned as well.
+// The locations are selected with the --location flag and the candidate locations of the manifest, as in a single subscription.
+func verifySubscription(ctx context.Context, session *azure.Session, checks []*serviceCheck, locationNames []string, manifestLocations []string) ([]*azure.VerificationResultList, *azure.AzureLocationList, error) {
+	azureLocations, err := getAllLocationsFromSubscription(ctx, session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	azureLocations = filterLocations(azureLocations, locationNames)
+	if !viper.GetBool("all-locations") {
+		if err := requireLocations(azureLocations, manifestLocations); err != nil {
+			return nil, nil, err
+		}
+		azureLocations = filterLocations(azureLocations, manifestLocations)
+	}
+
+	results, err := runServiceChecks(checks, azureLocations, ctx, session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The assignments of the subscription include the assignments inherited from its management groups.
+	// The --resource-group and --management-group scopes can't be given with several subscriptions.
+	azurePolicy := azure.NewAzurePolicy(session)
+	scope := azurePolicy.PolicyScope("", "")
+	assignments, err := azurePolicy.GetPolicyAssignments(ctx, scope)
+	if err != nil && ctx.Err() == nil {
+		return nil, nil, err
+	}
+	// The partial results of a cancelled verification are rendered without the assignments
+	policies := &policyAssignments{assignments: assignments, scope: scope, cancelled: err != nil}
+
+	for i, result := range results {
+		policies.apply(ctx, result, checks[i].vmSizes()...)
+	}
+
+	if _, err := requireRegistrations(ctx, session, results...); err != nil {
+		return nil, nil, err
+	}
+
+	for i, result := range results {
+		for _, value := range result.Value {
+			value.Service = checks[i].name
+		}
+	}
+
+	return results, azureLocations, nil
+}
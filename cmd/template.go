@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/armtemplate"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+)
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template <file>",
+	Short: "Verify the resources of an ARM template can be deployed to a location",
+	Long: `The template command provides the means to verify every resource of an ARM template, e.g. compiled
+with az bicep build, can be deployed to the target locations. The resource type and the API version of
+each resource must be offered in the location the resource is deployed to.
+
+The location of a resource is evaluated from the simple template expressions: the parameters, with the
+values of the --parameters file or the default values, the variables, and [resourceGroup().location]
+or [deployment().location], which are the target locations. The resources deployed to a fixed location,
+e.g. eastus2 or global, are verified in that location only. The target locations are assumed for the
+locations that can't be evaluated.`,
+	Example: `  azure-resource-verifier template main.json --parameters main.parameters.json -s <subscription-id> -l eastus2 -l westus3`,
+	Args:    cobra.ExactArgs(1),
+
+	RunE: cli.AzureClientWrapRunE(templateCommand),
+}
+
+// templateResult is the verification of a resource of the template in a location.
+type templateResult struct {
+	Resource *armtemplate.Resource     `json:"resource" yaml:"resource"`
+	Result   *azure.VerificationResult `json:"result" yaml:"result"`
+}
+
+func templateCommand(cmd *cobra.Command, args []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("template called")
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	parameters, err := cmd.Flags().GetString("parameters")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing parameters flag", err)
+	}
+
+	template, err := armtemplate.Load(args[0], parameters)
+	if err != nil {
+		return cli.CreateAzrErr("Error reading the template", err)
+	}
+
+	azureLocations, err := getAllLocationsFromSubscription(ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting the locations", err)
+	}
+
+	locationNames, err := cmd.Flags().GetStringArray("location")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+	locations := filterLocations(azureLocations, locationNames)
+
+	// The providers are read once for all the resources of the template
+	azureResourceProvider := azure.NewAzureResourceProvider(session)
+
+	results := make([]*azure.VerificationResultList, len(template.Resources))
+	for i, resource := range template.Resources {
+		resourceLocations := locations
+		if resource.Scope == armtemplate.FixedLocationScope {
+			resourceLocations = &azure.AzureLocationList{Value: []*azure.AzureLocation{fixedLocation(azureLocations, resource.Location)}}
+		}
+
+		results[i], err = azureResourceProvider.GetResourceTypeLocations(ctx, resourceLocations, resource.Type, resource.ApiVersion)
+		if err != nil {
+			return cli.CreateAzrErr("Error getting resource type locations", err)
+		}
+	}
+
+	policies, err := getPolicyAssignments(ctx, session)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		policies.apply(ctx, result)
+	}
+
+	if err := verifyRegistrations(cmd, ctx, session, results...); err != nil {
+		return err
+	}
+
+	// A target location supports the template if every resource deployed to it, and every resource
+	// deployed to a fixed location, is deployable
+	deployableLocations := locations
+	for i, result := range results {
+		if template.Resources[i].Scope != armtemplate.FixedLocationScope {
+			deployableLocations = deployableLocations.Intersection(result.DeployableLocations())
+		} else if len(result.DeployableLocations().Value) == 0 {
+			deployableLocations = &azure.AzureLocationList{Value: []*azure.AzureLocation{}}
+		}
+	}
+
+	names := make([]string, 0, len(deployableLocations.Value))
+	for _, location := range deployableLocations.Value {
+		names = append(names, location.Name)
+	}
+	cmd.PrintErrf("Locations supporting all resources: %s\n", strings.Join(names, ", "))
+
+	t := table.NewTable(table.Template)
+	data := []*templateResult{}
+
+	for i, resource := range template.Resources {
+		for _, result := range results[i].Value {
+			t.AppendRow([]string{resource.Name, resource.Type, resource.ApiVersion, result.Location.Name, strconv.FormatBool(result.IsDeployable()), result.ReasonCode, result.Evidence})
+			data = append(data, &templateResult{Resource: resource, Result: result})
+		}
+	}
+
+	t.SetData(data)
+
+	return renderTable(cmd, t)
+}
+
+// This function is used to get the fixed location of a resource from the locations of the subscription.
+// The locations that aren't regions, e.g. global, are verified by name.
+func fixedLocation(azureLocations *azure.AzureLocationList, name string) *azure.AzureLocation {
+	for _, location := range azureLocations.Value {
+		if strings.EqualFold(location.Name, name) {
+			return location
+		}
+	}
+
+	return &azure.AzureLocation{Name: name, DisplayName: name}
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+
+	templateCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+
+	templateCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
+	templateCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
+	templateCmd.MarkFlagsOneRequired("location", "all-locations")
+	templateCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	addVerificationFlags(templateCmd)
+	templateCmd.Flags().String("parameters", "", "The parameters file of the template, e.g. main.parameters.json")
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/manifest"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/nickdala/azure-resource-verifier/internal/terraform"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// terraformPlanCmd represents the verify terraform-plan command
+var terraformPlanCmd = &cobra.Command{
+	Use:   "terraform-plan <plan.json>",
+	Short: "Verify the azurerm resources of a Terraform plan can be deployed",
+	Long: `The terraform-plan command verifies the azurerm resources a Terraform plan creates or updates, before the plan
+is applied. The plan is the JSON output of terraform show -json.
+
+The resources are verified with the service checks of the verify command:
+  azurerm_redis_cache                                    redis
+  azurerm_postgresql_flexible_server                     postgresql
+  azurerm_mysql_flexible_server                          mysql
+  azurerm_mssql_database                                 sql
+  azurerm_linux_virtual_machine, azurerm_virtual_machine vm-sku (and the Windows and scale set variants)
+  azurerm_kubernetes_cluster                             aks
+  azurerm_service_plan, azurerm_linux_web_app            webapp (and the Windows variant)
+
+The location, SKU, version, high availability and zone attributes of the resources are verified.
+Each resource is verified in its planned location, or in the target locations if --location or
+--all-locations is given. The resources without a location, e.g. azurerm_mssql_database, are verified
+in the locations of the plan. A plan without any known location requires --location or --all-locations.`,
+	Example: `  terraform show -json plan.tfplan > plan.json
+  azure-resource-verifier verify terraform-plan plan.json -s <subscription-id>
+  azure-resource-verifier verify terraform-plan plan.json -s <subscription-id> -l eastus2 -l westus3`,
+	Args: cobra.ExactArgs(1),
+
+	RunE: cli.AzureClientWrapRunE(terraformPlanCommand),
+}
+
+func terraformPlanCommand(cmd *cobra.Command, args []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("verify terraform-plan called")
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	plan, err := terraform.LoadPlan(args[0])
+	if err != nil {
+		return cli.CreateAzrErr("Error reading the Terraform plan", err)
+	}
+
+	if len(plan.Unverified) > 0 {
+		cmd.PrintErrf("Resources not verified: %s\n", strings.Join(plan.Unverified, ", "))
+	}
+
+	locationNames, err := cmd.Flags().GetStringArray("location")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+	allLocations := viper.GetBool("all-locations")
+
+	// Without target locations, the resources are verified in their planned location, and the resources
+	// without a location in the locations of the plan
+	planned := len(locationNames) == 0 && !allLocations
+	if planned {
+		locationNames = plan.Locations()
+
+		// The locations of the plan are unknown when the locations are computed, don't verify every location instead
+		if len(locationNames) == 0 {
+			return cli.CreateAzrErr("No location in the plan, use --location or --all-locations", nil)
+		}
+	}
+
+	azureLocations, err := getAllLocationsFromSubscription(ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting the locations", err)
+	}
+
+	targetLocations := azureLocations
+	if !allLocations {
+		targetLocations = filterLocations(azureLocations, locationNames)
+	}
+
+	checks := make([]*serviceCheck, 0, len(plan.Resources))
+	for _, resource := range plan.Resources {
+		check, err := newServiceCheckFromManifest(resource.Service, manifest.Constraints{ZoneRedundant: resource.ZoneRedundant})
+		if err != nil {
+			return cli.CreateAzrErr("Error verifying "+resource.Address, err)
+		}
+
+		if planned && resource.Location != "" {
+			check.locations = &azure.AzureLocationList{Value: []*azure.AzureLocation{fixedLocation(azureLocations, resource.Location)}}
+		}
+
+		checks = append(checks, check)
+	}
+
+	results, err := runServiceChecks(checks, targetLocations, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error verifying services", err)
+	}
+
+	if err := verifyServiceResults(cmd, ctx, session, checks, results); err != nil {
+		return err
+	}
+
+	allResults := &azure.VerificationResultList{}
+	for _, result := range results {
+		allResults.Append(result)
+	}
+
+	// The unknown results, e.g. cancelled or with unreadable quota usages, are not failures
+	failures, unverified := []string{}, []string{}
+	for _, result := range allResults.Value {
+		switch {
+		case result.Status == azure.StatusUnsupported:
+			failures = append(failures, fmt.Sprintf("  %s in %s: %s", result.Service, result.Location.Name, result.ReasonCode))
+		case !result.IsDeployable():
+			unverified = append(unverified, fmt.Sprintf("  %s in %s: %s", result.Service, result.Location.Name, result.ReasonCode))
+		}
+	}
+
+	if len(failures) > 0 {
+		cmd.PrintErrf("Planned resources that will fail:\n%s\n", strings.Join(failures, "\n"))
+	}
+	if len(unverified) > 0 {
+		cmd.PrintErrf("Planned resources whose verification is unknown:\n%s\n", strings.Join(unverified, "\n"))
+	}
+	switch {
+	case len(allResults.Value) == 0:
+		cmd.PrintErrln("No planned resources were verified")
+	case len(failures) == 0 && len(unverified) == 0:
+		cmd.PrintErrln("All planned resources can be deployed")
+	}
+
+	return renderTable(cmd, newResultsTable(table.MultipleServices, allResults))
+}
+
+func init() {
+	verifyCmd.AddCommand(terraformPlanCmd)
+
+	terraformPlanCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+
+	terraformPlanCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to verify the resources in instead of their planned location. Can be specified multiple times")
+	terraformPlanCmd.Flags().Bool("all-locations", false, "Whether to verify the resources in all locations instead of their planned location")
+	terraformPlanCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	addVerificationFlags(terraformPlanCmd)
+}
@@ -0,0 +1,495 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/manifest"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+)
+
+// serviceCheck is a single service verification, e.g. redis, postgresql:ha or webapp:linux:container
+type serviceCheck struct {
+	name             string
+	service          string
+	sku              string
+	version          string
+	highAvailability string
+	operatingSystem  azure.AppServiceOS
+	publishType      azure.AppServicePublishType
+	planSku          string
+	edition          string
+	serviceObjective string
+	size             string
+	kubernetes       azure.KubernetesRequirements
+	resourceType     string
+	cores            int64
+	zoneRedundant    bool
+	// The locations the service is verified in instead of the target locations, e.g. the location of a planned resource
+	locations *azure.AzureLocationList
+}
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify multiple Azure services can be deployed to a location",
+	Long: `The verify command verifies, without any prompts, that all the given services can be deployed to a location.
+
+Each service is given with the --service flag and can have options separated by a colon:
+  redis
+  postgresql[:ha]
+  mysql[:ha]
+  sql[:<edition>][:<service-objective>][:zones]
+  vm-sku:<size>
+  aks[:<kubernetes-version>][:<node-vm-size>]
+  provider-type:<Namespace/resourceType>
+  webapp[:linux|windows][:code|container]
+
+The services can also be described in a workload manifest (arv.yaml) given with the --file flag.
+
+With --all-subscriptions or --scan-management-group, the services are verified in several subscriptions, since the
+offers and the restrictions differ per subscription. The Azure Policy assignments of each subscription, including
+the assignments inherited from its management groups, are evaluated and the report is given per subscription,
+location and service. As in the other commands, --management-group is the scope of the Azure Policy assignments
+evaluated in a single subscription.`,
+	Example: `  azure-resource-verifier verify -s <subscription-id> -l eastus2 --service redis --service postgresql:ha --service webapp:linux:container
+  azure-resource-verifier verify -s <subscription-id> -f arv.yaml
+  azure-resource-verifier verify --scan-management-group landing-zones -l eastus2 --service postgresql:ha`,
+
+	PreRunE: validateExportPolicyFlags,
+	RunE:    cli.AzureClientWrapRunE(verifyCommand),
+}
+
+func verifyCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("verify called")
+
+	checks, locationNames, err := getServiceChecks(cmd)
+	if err != nil {
+		return err
+	}
+
+	if isSubscriptionScan() {
+		return verifySubscriptions(cmd, cred, options, ctx, checks, locationNames)
+	}
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	azureLocations, err := getLocations(cmd, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	// The --all-locations flag overrides the candidate locations of the manifest
+	if allLocations, _ := cmd.Flags().GetBool("all-locations"); !allLocations {
+		if err := requireLocations(azureLocations, locationNames); err != nil {
+			return cli.CreateAzrErr("Error parsing the locations of the manifest", err)
+		}
+		azureLocations = filterLocations(azureLocations, locationNames)
+	}
+
+	results, err := runServiceChecks(checks, azureLocations, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error verifying services", err)
+	}
+
+	if err := verifyServiceResults(cmd, ctx, session, checks, results); err != nil {
+		return err
+	}
+
+	deployableLocations := azureLocations
+	for _, result := range results {
+		deployableLocations = deployableLocations.Intersection(result.DeployableLocations())
+	}
+
+	names := make([]string, 0, len(deployableLocations.Value))
+	for _, location := range deployableLocations.Value {
+		names = append(names, location.Name)
+	}
+	cmd.PrintErrf("Locations supporting all services: %s\n", strings.Join(names, ", "))
+
+	if err := exportPolicy(cmd, ctx, deployableLocations); err != nil {
+		return err
+	}
+
+	allResults := &azure.VerificationResultList{}
+	for _, result := range results {
+		allResults.Append(result)
+	}
+
+	return renderTable(cmd, newResultsTable(table.MultipleServices, allResults))
+}
+
+// This function is used to verify the results of the service checks against the Azure Policy assignments and the
+// resource provider registrations. The results are then labeled with the name of their check.
+func verifyServiceResults(cmd *cobra.Command, ctx context.Context, session *azure.Session, checks []*serviceCheck, results []*azure.VerificationResultList) error {
+	policies, err := getPolicyAssignments(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	for i, result := range results {
+		policies.apply(ctx, result, checks[i].vmSizes()...)
+	}
+
+	if err := verifyRegistrations(cmd, ctx, session, results...); err != nil {
+		return err
+	}
+
+	// Label the results with the requested service so they can be told apart in the output
+	for i, result := range results {
+		for _, value := range result.Value {
+			value.Service = checks[i].name
+		}
+	}
+
+	return nil
+}
+
+// This function is used to get the service checks from the --service flags and the manifest given with the --file flag.
+// The candidate locations of the manifest are returned as well, unless locations are given with the --location flag.
+func getServiceChecks(cmd *cobra.Command) ([]*serviceCheck, []string, error) {
+	services, err := cmd.Flags().GetStringArray("service")
+	if err != nil {
+		return nil, nil, cli.CreateAzrErr("Error parsing service flag", err)
+	}
+
+	checks := make([]*serviceCheck, 0, len(services))
+	for _, service := range services {
+		check, err := parseServiceCheck(service)
+		if err != nil {
+			return nil, nil, cli.CreateAzrErr("Error parsing service flag", err)
+		}
+		checks = append(checks, check)
+	}
+
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return nil, nil, cli.CreateAzrErr("Error parsing file flag", err)
+	}
+
+	if file == "" {
+		return checks, nil, nil
+	}
+
+	workload, err := manifest.Load(file)
+	if err != nil {
+		return nil, nil, cli.CreateAzrErr("Error loading the manifest", err)
+	}
+	log.Printf("Using manifest file: %s", file)
+
+	for _, service := range workload.Services {
+		check, err := newServiceCheckFromManifest(service, workload.Constraints)
+		if err != nil {
+			return nil, nil, cli.CreateAzrErr("Error loading the manifest", err)
+		}
+		checks = append(checks, check)
+	}
+
+	// The --location flag takes precedence over the candidate locations of the manifest
+	locations, _ := cmd.Flags().GetStringArray("location")
+	if len(locations) > 0 && len(workload.Locations) > 0 {
+		cmd.PrintErrf("The --location flag overrides the locations of the manifest: %s\n", strings.Join(workload.Locations, ", "))
+		return checks, nil, nil
+	}
+
+	// A manifest without locations doesn't verify every location by default
+	if allLocations, _ := cmd.Flags().GetBool("all-locations"); len(locations) == 0 && !allLocations && len(workload.Locations) == 0 {
+		return nil, nil, cli.CreateAzrErr("Error loading the manifest", fmt.Errorf("the manifest %s has no locations: use --location, --all-locations or add the locations to the manifest", file))
+	}
+
+	return checks, workload.Locations, nil
+}
+
+// This function is used to create a service check from a service of the manifest.
+func newServiceCheckFromManifest(service manifest.Service, constraints manifest.Constraints) (*serviceCheck, error) {
+	check := &serviceCheck{
+		name:             service.Name,
+		service:          service.Type,
+		sku:              service.Sku,
+		version:          service.Version,
+		highAvailability: service.HighAvailability,
+		planSku:          service.PlanSku,
+		edition:          service.Edition,
+		serviceObjective: service.ServiceObjective,
+		size:             service.Size,
+		kubernetes: azure.KubernetesRequirements{
+			KubernetesVersion: service.KubernetesVersion,
+			NodeVmSize:        service.NodeVmSize,
+			Zones:             service.Zones,
+		},
+		resourceType:  service.ResourceType,
+		cores:         service.Cores,
+		zoneRedundant: constraints.ZoneRedundant,
+	}
+
+	if check.name == "" {
+		check.name = defaultString(service.ResourceType, service.Type)
+	}
+
+	if check.service == "webapp" {
+		check.service = azure.WebAppService
+	}
+
+	if check.service == azure.WebAppService {
+		var err error
+		check.operatingSystem, err = azure.AppServiceOSFromString(defaultString(service.OperatingSystem, webAppOperatingSystemChoice.Default))
+		if err != nil {
+			return nil, err
+		}
+
+		check.publishType, err = azure.AppServicePublishTypeFromString(defaultString(service.PublishType, publishType.Default))
+		if err != nil {
+			return nil, err
+		}
+
+		if check.planSku != "" {
+			if _, err := azure.AppServicePlanTierFromSku(check.planSku); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return check, nil
+}
+
+func defaultString(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// This function is used to parse a service given with the --service flag.
+func parseServiceCheck(spec string) (*serviceCheck, error) {
+	parts := strings.Split(strings.ToLower(spec), ":")
+	check := &serviceCheck{name: spec, service: parts[0]}
+	options := parts[1:]
+
+	switch check.service {
+	case azure.RedisService:
+		if len(options) > 0 {
+			return nil, fmt.Errorf("invalid service %s: redis has no options", spec)
+		}
+	case azure.PostgresqlService, azure.MysqlService:
+		for _, option := range options {
+			if option != "ha" {
+				return nil, fmt.Errorf("invalid service %s: unknown %s option %s", spec, check.service, option)
+			}
+			check.highAvailability = manifest.HaZoneRedundant
+		}
+	case azure.SqlDatabaseService:
+		// The original case of the edition and service objective is kept
+		originalOptions := strings.Split(spec, ":")[1:]
+		for i, option := range options {
+			switch {
+			case option == "zones":
+				check.zoneRedundant = true
+			case isSqlDatabaseEdition(option):
+				check.edition = originalOptions[i]
+			case check.serviceObjective == "":
+				check.serviceObjective = originalOptions[i]
+			default:
+				return nil, fmt.Errorf("invalid service %s: unknown sql option %s", spec, option)
+			}
+		}
+	case azure.VirtualMachineSkuService:
+		if len(options) != 1 || options[0] == "" {
+			return nil, fmt.Errorf("invalid service %s: the size is required, e.g. vm-sku:Standard_D4s_v5", spec)
+		}
+		check.size = strings.Split(spec, ":")[1]
+	case azure.KubernetesService:
+		// The original case of the node VM size is kept
+		originalOptions := strings.Split(spec, ":")[1:]
+		for i, option := range options {
+			switch {
+			case option == "":
+				return nil, fmt.Errorf("invalid service %s: empty aks option", spec)
+			case option[0] >= '0' && option[0] <= '9' && check.kubernetes.KubernetesVersion == "":
+				check.kubernetes.KubernetesVersion = option
+			case check.kubernetes.NodeVmSize == "":
+				check.kubernetes.NodeVmSize = originalOptions[i]
+			default:
+				return nil, fmt.Errorf("invalid service %s: unknown aks option %s", spec, option)
+			}
+		}
+	case azure.ResourceTypeService:
+		if len(options) != 1 || options[0] == "" {
+			return nil, fmt.Errorf("invalid service %s: the resource type is required, e.g. provider-type:Microsoft.App/managedEnvironments", spec)
+		}
+		check.resourceType = strings.Split(spec, ":")[1]
+		if _, _, err := azure.ParseResourceType(check.resourceType); err != nil {
+			return nil, fmt.Errorf("invalid service %s: %w", spec, err)
+		}
+	case "webapp", azure.WebAppService:
+		check.service = azure.WebAppService
+		check.operatingSystem = azure.Linux
+		check.publishType = azure.Code
+		for _, option := range options {
+			if osType, err := azure.AppServiceOSFromString(option); err == nil {
+				check.operatingSystem = osType
+			} else if publish, err := azure.AppServicePublishTypeFromString(option); err == nil {
+				check.publishType = publish
+			} else {
+				return nil, fmt.Errorf("invalid service %s: unknown webapp option %s", spec, option)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid service %s: supported services are redis, postgresql, mysql, sql, vm-sku, aks, provider-type and webapp", spec)
+	}
+
+	return check, nil
+}
+
+// This function is used to run all the service checks concurrently.
+// The results are returned in the same order as the checks.
+func runServiceChecks(checks []*serviceCheck, locations *azure.AzureLocationList, ctx context.Context, session *azure.Session) ([]*azure.VerificationResultList, error) {
+	results := make([]*azure.VerificationResultList, len(checks))
+	errs := make([]error, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(idx int, check *serviceCheck) {
+			defer wg.Done()
+			log.Printf("Verifying service %s", check.name)
+			checkLocations := locations
+			if check.locations != nil {
+				checkLocations = check.locations
+			}
+			results[idx], errs[idx] = check.run(checkLocations, ctx, session)
+		}(i, check)
+	}
+
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (c *serviceCheck) run(locations *azure.AzureLocationList, ctx context.Context, session *azure.Session) (*azure.VerificationResultList, error) {
+	var results *azure.VerificationResultList
+	var err error
+
+	switch c.service {
+	case azure.RedisService:
+		results, err = azure.NewAzureRedisCache(session).GetRedisLocations(ctx, locations)
+	case azure.PostgresqlService:
+		results, err = azure.NewAzurePostgresqlFlexibleServer(session).GetPostgresqlLocations(ctx, locations)
+	case azure.MysqlService:
+		results, err = azure.NewAzureMysqlFlexibleServer(session).GetMysqlLocations(ctx, locations)
+	case azure.SqlDatabaseService:
+		results, err = azure.NewAzureSqlDatabase(session).GetSqlDatabaseLocations(ctx, locations, c.edition, c.serviceObjective)
+	case azure.VirtualMachineSkuService:
+		results, err = azure.NewAzureVirtualMachineSku(session).GetVirtualMachineSkuLocations(ctx, locations, c.size)
+	case azure.KubernetesService:
+		results, err = azure.NewAzureKubernetesService(session).GetKubernetesLocations(ctx, locations, c.kubernetes)
+	case azure.ResourceTypeService:
+		results, err = azure.NewAzureResourceProvider(session).GetResourceTypeLocations(ctx, locations, c.resourceType, "")
+	case azure.WebAppService:
+		results, err = azure.NewAzureAppService(session).GetAppServiceLocations(ctx, locations, c.operatingSystem, c.publishType, c.planSku)
+	default:
+		return nil, fmt.Errorf("unknown service %s", c.service)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error verifying %s: %w", c.name, err)
+	}
+
+	if err := attachQuotas(ctx, session, c.service, locations, results); err != nil {
+		return nil, fmt.Errorf("error getting the quota usages of %s: %w", c.name, err)
+	}
+
+	c.applyRequirements(results)
+
+	return results, nil
+}
+
+// vmSizes returns the virtual machine sizes the service of the check is deployed with.
+func (c *serviceCheck) vmSizes() []string {
+	switch c.service {
+	case azure.VirtualMachineSkuService:
+		return []string{c.size}
+	case azure.KubernetesService:
+		return []string{c.kubernetes.NodeVmSize}
+	default:
+		return nil
+	}
+}
+
+// applyRequirements marks the locations that don't meet the options of the check as unsupported.
+func (c *serviceCheck) applyRequirements(results *azure.VerificationResultList) {
+	switch c.highAvailability {
+	case manifest.HaZoneRedundant:
+		results.RequireFeature(azure.HighAvailabilityFeature, azure.HaNotSupportedReason)
+	case manifest.HaSameZone:
+		results.RequireFeature(azure.SameZoneHaFeature, azure.HaNotSupportedReason)
+	}
+
+	if c.version != "" {
+		results.RequireFeature(azure.VersionFeature(c.version), azure.VersionNotSupportedReason)
+	}
+
+	if strings.HasPrefix(c.sku, "Enterprise") {
+		results.RequireFeature(azure.RedisEnterpriseFeature, azure.SkuNotSupportedReason)
+	}
+
+	if c.cores > 0 {
+		results.RequireQuota(azure.QuotaName{Provider: azure.ComputeQuotaProvider, Name: azure.CoresQuotaName}, c.cores)
+	}
+
+	if c.zoneRedundant {
+		// App Service doesn't report zone redundancy per location and AKS needs the node VM size
+		if c.service == azure.WebAppService || (c.service == azure.KubernetesService && c.kubernetes.NodeVmSize == "") {
+			log.Printf("Zone redundancy can't be verified for %s", c.name)
+		} else {
+			results.RequireFeature(azure.ZoneRedundancyFeature, azure.ZonesNotSupportedReason)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+	verifyCmd.Flags().Bool("all-subscriptions", false, "Whether to verify the services in all the subscriptions the credential can access")
+	verifyCmd.Flags().String("scan-management-group", "", "The management group to verify the services in all the subscriptions of, including the subscriptions of its child management groups")
+	verifyCmd.Flags().Int("subscription-concurrency", defaultSubscriptionConcurrency, "The number of subscriptions verified at the same time with --all-subscriptions or --scan-management-group")
+	verifyCmd.MarkFlagsMutuallyExclusive("subscription-id", "all-subscriptions", "scan-management-group")
+
+	verifyCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
+	verifyCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
+	verifyCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	// The scope of the Azure Policy assignments and the registrations are specific to one subscription,
+	// the assignments of each scanned subscription include the assignments inherited from its management groups
+	addVerificationFlags(verifyCmd)
+	verifyCmd.MarkFlagsMutuallyExclusive("resource-group", "all-subscriptions", "scan-management-group")
+	verifyCmd.MarkFlagsMutuallyExclusive("management-group", "all-subscriptions", "scan-management-group")
+	verifyCmd.MarkFlagsMutuallyExclusive("fix", "all-subscriptions", "scan-management-group")
+
+	verifyCmd.Flags().String("export-policy", "", "The file to export the locations supporting all services to as an Azure Policy allowed locations definition (.json, .bicep or .tf)")
+	verifyCmd.Flags().String(policyEffectChoice.Name, policyEffectChoice.Default, policyEffectChoice.Description)
+
+	verifyCmd.Flags().StringArray("service", []string{}, "The service to verify, e.g. redis, postgresql:ha or webapp:linux:container. Can be specified multiple times")
+	verifyCmd.Flags().StringP("file", "f", "", fmt.Sprintf("The workload manifest file describing the services to verify, e.g. %s", manifest.DefaultFileName))
+	verifyCmd.MarkFlagsOneRequired("service", "file")
+
+	// The locations can come from the manifest
+	verifyCmd.MarkFlagsOneRequired("location", "all-locations", "file")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cli"
+	"github.com/nickdala/azure-resource-verifier/internal/table"
+	"github.com/spf13/cobra"
+)
+
+// vmSkuCmd represents the vm-sku command
+var vmSkuCmd = &cobra.Command{
+	Use:   "vm-sku",
+	Short: "Verify a Virtual Machine size can be deployed to a location",
+	Long: `The vm-sku command provides the means to verify a Virtual Machine size is offered in a location
+and is not restricted for the subscription. The availability zones the size can be deployed to are reported per location.`,
+	Example: `  azure-resource-verifier vm-sku -s <subscription-id> -l eastus2 --size Standard_D4s_v5`,
+
+	RunE: cli.AzureClientWrapRunE(vmSkuCommand),
+}
+
+func vmSkuCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("vm-sku called")
+
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
+
+	size, err := cmd.Flags().GetString("size")
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing size flag", err)
+	}
+
+	locations, err := getLocations(cmd, ctx, session)
+	if err != nil {
+		return cli.CreateAzrErr("Error parsing location flag", err)
+	}
+
+	azureVirtualMachineSku := azure.NewAzureVirtualMachineSku(session)
+
+	vmSkuResults, err := azureVirtualMachineSku.GetVirtualMachineSkuLocations(ctx, locations, size)
+	if err != nil {
+		return cli.CreateAzrErr("Error getting Virtual Machine SKU locations", err)
+	}
+
+	if err := attachQuotas(ctx, session, azure.VirtualMachineSkuService, locations, vmSkuResults); err != nil {
+		return cli.CreateAzrErr("Error getting Virtual Machine SKU quota usages", err)
+	}
+
+	if err := verifyPolicies(cmd, ctx, session, vmSkuResults, size); err != nil {
+		return err
+	}
+
+	if err := verifyRegistrations(cmd, ctx, session, vmSkuResults); err != nil {
+		return err
+	}
+
+	return renderTable(cmd, newResultsTable(table.VirtualMachineSku, vmSkuResults))
+}
+
+func init() {
+	rootCmd.AddCommand(vmSkuCmd)
+
+	vmSkuCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
+
+	vmSkuCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
+	vmSkuCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
+	vmSkuCmd.MarkFlagsOneRequired("location", "all-locations")
+	vmSkuCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
+
+	addVerificationFlags(vmSkuCmd)
+
+	vmSkuCmd.Flags().String("size", "", "The Virtual Machine size, e.g. Standard_D4s_v5")
+	if err := vmSkuCmd.MarkFlagRequired("size"); err != nil {
+		vmSkuCmd.Printf("Error marking flag required: %s", err)
+		os.Exit(1)
+	}
+}
@@ -6,10 +6,8 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/nickdala/azure-resource-verifier/internal/azure"
 	"github.com/nickdala/azure-resource-verifier/internal/cli"
 	"github.com/nickdala/azure-resource-verifier/internal/table"
@@ -42,11 +40,15 @@ var webAppCmd = &cobra.Command{
 	RunE: cli.AzureClientWrapRunE(appServiceCommand),
 }
 
-func appServiceCommand(cmd *cobra.Command, _ []string, cred *azidentity.DefaultAzureCredential, ctx context.Context) error {
-	fmt.Println("web-app called")
+func appServiceCommand(cmd *cobra.Command, _ []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error {
+	cmd.PrintErrln("web-app called")
 
-	subscriptionId := viper.GetString("subscription-id")
-	log.Printf("subscription-id: %s", subscriptionId)
+	subscriptionId, err := getSubscriptionId()
+	if err != nil {
+		return err
+	}
+
+	session := azure.NewSession(cred, subscriptionId, options)
 
 	os := viper.GetString(webAppOperatingSystemChoice.Name)
 	if valid := webAppOperatingSystemChoice.IsValidChoice(os); !valid {
@@ -68,55 +70,40 @@ func appServiceCommand(cmd *cobra.Command, _ []string, cred *azidentity.DefaultA
 		return cli.CreateAzrErr("Error parsing publish type flag", err)
 	}
 
-	azureLocations, err := getLocations(cmd, cred, ctx, subscriptionId)
+	azureLocations, err := getLocations(cmd, ctx, session)
 	if err != nil {
 		return cli.CreateAzrErr("Error parsing location flag", err)
 	}
 
-	azureAppService := azure.NewAzureAppService(cred, ctx, subscriptionId)
-	appServiceLocations, err := azureAppService.GetAppServiceLocations(azureLocations, osType, publishType)
+	azureAppService := azure.NewAzureAppService(session)
+	appServiceResults, err := azureAppService.GetAppServiceLocations(ctx, azureLocations, osType, publishType, "")
 	if err != nil {
 		return cli.CreateAzrErr("Error getting App Service locations", err)
 	}
 
-	var data [][]string
-
-	seenRegions := make(map[string]struct{})
-
-	for _, location := range appServiceLocations.Value {
-		data = append(data, []string{location.Name, location.DisplayName, "true"})
-		seenRegions[location.Name] = struct{}{}
+	if err := verifyPolicies(cmd, ctx, session, appServiceResults); err != nil {
+		return err
 	}
 
-	// Now add the regions that were not returned by the API
-	for _, location := range azureLocations.Value {
-		if _, ok := seenRegions[location.Name]; !ok {
-			data = append(data, []string{location.Name, location.DisplayName, "false"})
-		}
+	if err := verifyRegistrations(cmd, ctx, session, appServiceResults); err != nil {
+		return err
 	}
 
-	table := table.NewTable(table.WebApp)
-	table.AppendBulk(data)
-	table.Render()
-
-	return nil
+	return renderTable(cmd, newResultsTable(table.WebApp, appServiceResults))
 }
 
 func init() {
 	rootCmd.AddCommand(webAppCmd)
 
-	webAppCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id")
-	// Required
-	if err := webAppCmd.MarkFlagRequired("subscription-id"); err != nil {
-		webAppCmd.Printf("Error marking flag required: %s", err)
-		os.Exit(1)
-	}
+	webAppCmd.Flags().StringP("subscription-id", "s", "", "The Azure subscription id. Defaults to AZURE_SUBSCRIPTION_ID or the default subscription of the Azure CLI")
 
 	webAppCmd.Flags().StringArrayP("location", "l", []string{}, "The Azure location to list the capabilities. Can be specified multiple times")
 	webAppCmd.Flags().Bool("all-locations", false, "Whether to list capabilities for all locations")
 	webAppCmd.MarkFlagsOneRequired("location", "all-locations")
 	webAppCmd.MarkFlagsMutuallyExclusive("location", "all-locations")
 
+	addVerificationFlags(webAppCmd)
+
 	webAppCmd.Flags().StringP(webAppOperatingSystemChoice.Name, "o", webAppOperatingSystemChoice.Default, webAppOperatingSystemChoice.Description)
 	webAppCmd.Flags().StringP(publishType.Name, "p", publishType.Default, publishType.Description)
 
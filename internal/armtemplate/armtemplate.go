@@ -0,0 +1,294 @@
+package armtemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+)
+
+// LocationScope tells where a resource of a template is deployed.
+type LocationScope string
+
+const (
+	// The resource is deployed to the target location, e.g. [resourceGroup().location]
+	TargetLocationScope LocationScope = "target"
+	// The resource is deployed to a fixed location, e.g. eastus2 or global
+	FixedLocationScope LocationScope = "fixed"
+	// The resource has no location, e.g. Microsoft.Authorization/roleAssignments
+	NoLocationScope LocationScope = "none"
+)
+
+// The resource type of the nested deployments, e.g. the Bicep modules
+const deploymentsResourceType = "Microsoft.Resources/deployments"
+
+// The template expressions that can be evaluated: a parameter or a variable
+var referenceExpression = regexp.MustCompile(`^\[\s*(parameters|variables)\(\s*'([^']+)'\s*\)\s*\]$`)
+
+// The maximum number of parameters and variables resolved to evaluate an expression
+const maxEvaluationDepth = 8
+
+// The template expressions of the target location
+var targetLocationExpression = regexp.MustCompile(`^\[\s*(resourceGroup|deployment)\(\)\.location\s*\]$`)
+
+// Resource is a resource of a template, with its location resolved.
+type Resource struct {
+	Name       string        `json:"name" yaml:"name"`
+	Type       string        `json:"type" yaml:"type"`
+	ApiVersion string        `json:"apiVersion" yaml:"apiVersion"`
+	Scope      LocationScope `json:"scope" yaml:"scope"`
+	Location   string        `json:"location,omitempty" yaml:"location,omitempty"`
+}
+
+// Template is an ARM template, e.g. compiled from Bicep.
+type Template struct {
+	Resources []*Resource
+}
+
+// The parts of an ARM template used by the verification
+type templateDocument struct {
+	Parameters map[string]struct {
+		DefaultValue any `json:"defaultValue"`
+	} `json:"parameters"`
+	Variables map[string]any `json:"variables"`
+	// An array of resources, or an object of symbolic names and resources (languageVersion 2.0)
+	Resources json.RawMessage `json:"resources"`
+}
+
+type resourceDocument struct {
+	Type       string          `json:"type"`
+	ApiVersion string          `json:"apiVersion"`
+	Name       any             `json:"name"`
+	Location   any             `json:"location"`
+	Resources  json.RawMessage `json:"resources"`
+	Properties struct {
+		Template   *templateDocument `json:"template"`
+		Parameters map[string]struct {
+			Value any `json:"value"`
+		} `json:"parameters"`
+	} `json:"properties"`
+}
+
+// The parameters file of a template
+type parametersDocument struct {
+	Parameters map[string]struct {
+		Value any `json:"value"`
+	} `json:"parameters"`
+}
+
+// scope is the parameters and variables a template expression is evaluated with.
+type scope struct {
+	parameters map[string]any
+	variables  map[string]any
+}
+
+// Load reads an ARM template and resolves the location of its resources. The values of the parameters file,
+// if given, override the default values of the parameters.
+func Load(path string, parametersPath string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the template %s: %w", path, err)
+	}
+
+	parameters := map[string]any{}
+	if parametersPath != "" {
+		parametersData, err := os.ReadFile(parametersPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the parameters %s: %w", parametersPath, err)
+		}
+
+		document := &parametersDocument{}
+		if err := json.Unmarshal(parametersData, document); err != nil {
+			return nil, fmt.Errorf("failed to parse the parameters %s: %w", parametersPath, err)
+		}
+
+		for name, parameter := range document.Parameters {
+			parameters[name] = parameter.Value
+		}
+	}
+
+	template, err := Parse(data, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the template %s: %w", path, err)
+	}
+
+	return template, nil
+}
+
+// Parse parses an ARM template and resolves the location of its resources with the parameter values.
+func Parse(data []byte, parameters map[string]any) (*Template, error) {
+	document := &templateDocument{}
+	if err := json.Unmarshal(data, document); err != nil {
+		return nil, err
+	}
+
+	template := &Template{}
+	if err := template.addResources(document, parameters); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// addResources adds the resources of the template, and of its nested templates.
+func (t *Template) addResources(document *templateDocument, parameters map[string]any) error {
+	s := &scope{parameters: map[string]any{}, variables: document.Variables}
+	for name, parameter := range document.Parameters {
+		s.parameters[name] = parameter.DefaultValue
+	}
+	for name, value := range parameters {
+		s.parameters[name] = value
+	}
+
+	resources, err := parseResources(document.Resources)
+	if err != nil {
+		return err
+	}
+
+	return t.addResourceList(resources, "", s)
+}
+
+func (t *Template) addResourceList(resources []*resourceDocument, parentType string, s *scope) error {
+	for _, resource := range resources {
+		resourceType := resource.Type
+		// The type of a child resource is relative to the type of its parent
+		if parentType != "" && !strings.Contains(resourceType, ".") {
+			resourceType = parentType + "/" + resourceType
+		}
+
+		t.Resources = append(t.Resources, newResource(resource, resourceType, s))
+
+		children, err := parseResources(resource.Resources)
+		if err != nil {
+			return err
+		}
+		if err := t.addResourceList(children, resourceType, s); err != nil {
+			return err
+		}
+
+		// The parameters of a nested template are evaluated in the scope of the parent template
+		if strings.EqualFold(resourceType, deploymentsResourceType) && resource.Properties.Template != nil {
+			parameters := map[string]any{}
+			for name, parameter := range resource.Properties.Parameters {
+				parameters[name] = s.evaluate(parameter.Value)
+			}
+			if err := t.addResources(resource.Properties.Template, parameters); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func newResource(resource *resourceDocument, resourceType string, s *scope) *Resource {
+	r := &Resource{
+		Name:       fmt.Sprint(s.evaluate(resource.Name)),
+		Type:       resourceType,
+		ApiVersion: resource.ApiVersion,
+		Scope:      NoLocationScope,
+	}
+
+	if resource.Location == nil {
+		return r
+	}
+
+	location, ok := s.evaluate(resource.Location).(string)
+	if !ok || isExpression(location) {
+		// The location can't be evaluated, e.g. [parameters('locations')[0]], the target location is assumed
+		log.Printf("The location %v of %s can't be evaluated, the target location is assumed", resource.Location, r.Name)
+		r.Scope = TargetLocationScope
+		return r
+	}
+
+	if location == "" {
+		r.Scope = TargetLocationScope
+		return r
+	}
+
+	r.Scope = FixedLocationScope
+	r.Location = azure.NormalizeLocation(location)
+	return r
+}
+
+// parseResources parses the resources of a template, either an array or an object of symbolic names.
+func parseResources(data json.RawMessage) ([]*resourceDocument, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	resources := []*resourceDocument{}
+	if err := json.Unmarshal(data, &resources); err == nil {
+		return resources, nil
+	}
+
+	symbolicResources := map[string]*resourceDocument{}
+	if err := json.Unmarshal(data, &symbolicResources); err != nil {
+		return nil, fmt.Errorf("invalid resources: %w", err)
+	}
+
+	// The symbolic names are sorted so the resources are always in the same order
+	names := make([]string, 0, len(symbolicResources))
+	for name := range symbolicResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		resources = append(resources, symbolicResources[name])
+	}
+	return resources, nil
+}
+
+// evaluate evaluates the simple template expressions: parameters, variables and the target location,
+// which is evaluated to an empty string. The other expressions are returned as is.
+func (s *scope) evaluate(value any) any {
+	return s.evaluateDepth(value, 0)
+}
+
+func (s *scope) evaluateDepth(value any, depth int) any {
+	expression, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	// [[ escapes a literal string starting with [
+	if strings.HasPrefix(expression, "[[") {
+		return expression[1:]
+	}
+
+	if targetLocationExpression.MatchString(expression) {
+		return ""
+	}
+
+	match := referenceExpression.FindStringSubmatch(expression)
+	if match == nil {
+		return expression
+	}
+
+	values := s.parameters
+	if match[1] == "variables" {
+		values = s.variables
+	}
+
+	resolved, ok := values[match[2]]
+	if !ok {
+		return expression
+	}
+
+	// The default value of a parameter can be an expression as well, e.g. [resourceGroup().location].
+	// The depth is limited, e.g. when variables reference each other.
+	if depth < maxEvaluationDepth {
+		return s.evaluateDepth(resolved, depth+1)
+	}
+	return resolved
+}
+
+func isExpression(value string) bool {
+	return strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]")
+}
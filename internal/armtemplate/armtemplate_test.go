@@ -0,0 +1,160 @@
+package armtemplate
+
+import (
+	"testing"
+)
+
+const testTemplate = `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+  "parameters": {
+    "location": {"type": "string", "defaultValue": "[resourceGroup().location]"},
+    "dnsLocation": {"type": "string", "defaultValue": "global"}
+  },
+  "variables": {
+    "backupLocation": "West US 3",
+    "serverName": "sql-server"
+  },
+  "resources": [
+    {
+      "type": "Microsoft.Sql/servers",
+      "apiVersion": "2023-08-01-preview",
+      "name": "[variables('serverName')]",
+      "location": "[parameters('location')]",
+      "resources": [
+        {"type": "databases", "apiVersion": "2023-08-01-preview", "name": "db", "location": "[variables('backupLocation')]"}
+      ]
+    },
+    {
+      "type": "Microsoft.Network/dnsZones",
+      "apiVersion": "2018-05-01",
+      "name": "contoso.com",
+      "location": "[parameters('dnsLocation')]"
+    },
+    {
+      "type": "Microsoft.Authorization/roleAssignments",
+      "apiVersion": "2022-04-01",
+      "name": "[guid(resourceGroup().id)]"
+    },
+    {
+      "type": "Microsoft.Web/sites",
+      "apiVersion": "2023-12-01",
+      "name": "app",
+      "location": "[parameters('locations')[0]]"
+    },
+    {
+      "type": "Microsoft.Resources/deployments",
+      "apiVersion": "2022-09-01",
+      "name": "module",
+      "properties": {
+        "mode": "Incremental",
+        "parameters": {
+          "cacheLocation": {"value": "[variables('backupLocation')]"}
+        },
+        "template": {
+          "parameters": {
+            "cacheLocation": {"type": "string"}
+          },
+          "resources": {
+            "cache": {
+              "type": "Microsoft.Cache/redis",
+              "apiVersion": "2024-03-01",
+              "name": "cache",
+              "location": "[parameters('cacheLocation')]"
+            }
+          }
+        }
+      }
+    }
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters map[string]any
+		want       []Resource
+	}{
+		{
+			name: "Test default values",
+			want: []Resource{
+				{Name: "sql-server", Type: "Microsoft.Sql/servers", ApiVersion: "2023-08-01-preview", Scope: TargetLocationScope},
+				{Name: "db", Type: "Microsoft.Sql/servers/databases", ApiVersion: "2023-08-01-preview", Scope: FixedLocationScope, Location: "westus3"},
+				{Name: "contoso.com", Type: "Microsoft.Network/dnsZones", ApiVersion: "2018-05-01", Scope: FixedLocationScope, Location: "global"},
+				{Name: "[guid(resourceGroup().id)]", Type: "Microsoft.Authorization/roleAssignments", ApiVersion: "2022-04-01", Scope: NoLocationScope},
+				{Name: "app", Type: "Microsoft.Web/sites", ApiVersion: "2023-12-01", Scope: TargetLocationScope},
+				{Name: "module", Type: "Microsoft.Resources/deployments", ApiVersion: "2022-09-01", Scope: NoLocationScope},
+				{Name: "cache", Type: "Microsoft.Cache/redis", ApiVersion: "2024-03-01", Scope: FixedLocationScope, Location: "westus3"},
+			},
+		},
+		{
+			name:       "Test parameter values",
+			parameters: map[string]any{"location": "East US 2"},
+			want: []Resource{
+				{Name: "sql-server", Type: "Microsoft.Sql/servers", ApiVersion: "2023-08-01-preview", Scope: FixedLocationScope, Location: "eastus2"},
+				{Name: "db", Type: "Microsoft.Sql/servers/databases", ApiVersion: "2023-08-01-preview", Scope: FixedLocationScope, Location: "westus3"},
+				{Name: "contoso.com", Type: "Microsoft.Network/dnsZones", ApiVersion: "2018-05-01", Scope: FixedLocationScope, Location: "global"},
+				{Name: "[guid(resourceGroup().id)]", Type: "Microsoft.Authorization/roleAssignments", ApiVersion: "2022-04-01", Scope: NoLocationScope},
+				{Name: "app", Type: "Microsoft.Web/sites", ApiVersion: "2023-12-01", Scope: TargetLocationScope},
+				{Name: "module", Type: "Microsoft.Resources/deployments", ApiVersion: "2022-09-01", Scope: NoLocationScope},
+				{Name: "cache", Type: "Microsoft.Cache/redis", ApiVersion: "2024-03-01", Scope: FixedLocationScope, Location: "westus3"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := Parse([]byte(testTemplate), tt.parameters)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if len(template.Resources) != len(tt.want) {
+				t.Fatalf("Parse() got %d resources, want %d", len(template.Resources), len(tt.want))
+			}
+
+			for i, resource := range template.Resources {
+				if *resource != tt.want[i] {
+					t.Errorf("Parse() resource %d = %+v, want %+v", i, *resource, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	s := &scope{
+		parameters: map[string]any{"tags": map[string]any{"env": "dev"}},
+		variables:  map[string]any{"a": "[variables('b')]", "b": "[variables('a')]"},
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{name: "Test escaped literal", value: "[[not an expression]", want: "[not an expression]"},
+		{name: "Test deployment location", value: "[deployment().location]", want: ""},
+		{name: "Test unknown parameter", value: "[parameters('unknown')]", want: "[parameters('unknown')]"},
+		{name: "Test object parameter", value: "[parameters('tags')]", want: map[string]any{"env": "dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.evaluate(tt.value)
+			if want, ok := tt.want.(string); ok {
+				if got != want {
+					t.Errorf("evaluate() = %v, want %v", got, want)
+				}
+			} else if _, ok := got.(map[string]any); !ok {
+				t.Errorf("evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Test variables referencing each other", func(t *testing.T) {
+		got, ok := s.evaluate("[variables('a')]").(string)
+		if !ok || !isExpression(got) {
+			t.Errorf("evaluate() = %v, want an expression", got)
+		}
+	})
+}
@@ -0,0 +1,327 @@
+package azd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+// The files of an azd project
+const (
+	ProjectFileName    = "azure.yaml"
+	environmentDir     = ".azure"
+	configFileName     = "config.json"
+	envFileName        = ".env"
+	defaultInfraPath   = "infra"
+	defaultInfraModule = "main"
+)
+
+// The environment variables of an azd environment used by the verification
+const (
+	LocationVariable       = "AZURE_LOCATION"
+	SubscriptionIdVariable = "AZURE_SUBSCRIPTION_ID"
+)
+
+// The resource types of the hosting targets that have no dedicated service verification
+const (
+	containerAppResourceType = "Microsoft.App/managedEnvironments"
+	staticWebAppResourceType = "Microsoft.Web/staticSites"
+	cosmosDbResourceType     = "Microsoft.DocumentDB/databaseAccounts"
+)
+
+// Project is an azd project, described as the services of a workload manifest.
+type Project struct {
+	Name     string
+	Services []manifest.Service
+	// The environment of the project, nil if the project has no environment
+	Environment *Environment
+}
+
+// Environment is an azd environment, i.e. the variables of .azure/<environment>/.env.
+type Environment struct {
+	Name      string
+	Variables map[string]string
+}
+
+// Location returns the name of the AZURE_LOCATION of the environment, e.g. eastus2 for East US 2.
+func (e *Environment) Location() string {
+	return azure.NormalizeLocation(e.Variables[LocationVariable])
+}
+
+// SubscriptionId returns the AZURE_SUBSCRIPTION_ID of the environment.
+func (e *Environment) SubscriptionId() string {
+	return e.Variables[SubscriptionIdVariable]
+}
+
+// The parts of azure.yaml used by the verification
+type projectDocument struct {
+	Name  string `yaml:"name"`
+	Infra struct {
+		Path   string `yaml:"path"`
+		Module string `yaml:"module"`
+	} `yaml:"infra"`
+	Services map[string]struct {
+		Host   string `yaml:"host"`
+		Image  string `yaml:"image"`
+		Docker *struct {
+			Path string `yaml:"path"`
+		} `yaml:"docker"`
+	} `yaml:"services"`
+	// The resources of the azd composability, e.g. type: db.postgres
+	Resources map[string]struct {
+		Type string `yaml:"type"`
+	} `yaml:"resources"`
+}
+
+// The suffix of the infra parameters that name a resource, e.g. postgresServerName
+const resourceNameSuffix = "name"
+
+// The services verified for the keywords of the infra parameter names, e.g. postgresDatabaseName.
+// The keywords are matched in order, so postgresql and mysql are matched before sql.
+var databaseKeywords = []struct {
+	keyword string
+	service manifest.Service
+}{
+	{keyword: "postgres", service: manifest.Service{Type: azure.PostgresqlService}},
+	{keyword: "mysql", service: manifest.Service{Type: azure.MysqlService}},
+	{keyword: "redis", service: manifest.Service{Type: azure.RedisService}},
+	{keyword: "cosmos", service: manifest.Service{Type: azure.ResourceTypeService, ResourceType: cosmosDbResourceType}},
+	{keyword: "sql", service: manifest.Service{Type: azure.SqlDatabaseService}},
+}
+
+// The services verified for the resource types of the azd composability
+var resourceTypeServices = map[string]manifest.Service{
+	"db.postgres":       {Type: azure.PostgresqlService},
+	"db.mysql":          {Type: azure.MysqlService},
+	"db.redis":          {Type: azure.RedisService},
+	"db.mongo":          {Type: azure.ResourceTypeService, ResourceType: cosmosDbResourceType},
+	"db.cosmos":         {Type: azure.ResourceTypeService, ResourceType: cosmosDbResourceType},
+	"host.containerapp": {Type: azure.ResourceTypeService, ResourceType: containerAppResourceType},
+}
+
+// LoadProject reads the azd project of the directory: the hosting targets of the services of azure.yaml and the
+// databases referenced by the parameters of the infra module. The environment is read from .azure/<environment>/.env,
+// the default environment of the project is used if no environment is given.
+func LoadProject(dir string, environment string) (*Project, error) {
+	path := filepath.Join(dir, ProjectFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the azd project %s: %w", path, err)
+	}
+
+	document := &projectDocument{}
+	if err := yaml.Unmarshal(data, document); err != nil {
+		return nil, fmt.Errorf("failed to parse the azd project %s: %w", path, err)
+	}
+
+	project := &Project{Name: document.Name}
+	project.addHostServices(document)
+	project.addResourceServices(document)
+
+	parameters, err := readParameterNames(dir, document)
+	if err != nil {
+		return nil, err
+	}
+	project.addDatabaseServices(parameters)
+
+	project.Environment, err = loadEnvironment(dir, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// addHostServices adds the services of the hosting targets, e.g. host: appservice.
+func (p *Project) addHostServices(document *projectDocument) {
+	for _, name := range sortedKeys(document.Services) {
+		service := document.Services[name]
+
+		switch service.Host {
+		case "appservice":
+			publishType := "code"
+			if service.Docker != nil || service.Image != "" {
+				publishType = "container"
+			}
+			p.add(manifest.Service{Name: name, Type: azure.WebAppService, OperatingSystem: "linux", PublishType: publishType})
+		case "function":
+			// Azure Functions are hosted on App Service
+			p.add(manifest.Service{Name: name, Type: azure.WebAppService, OperatingSystem: "linux", PublishType: "code"})
+		case "containerapp":
+			p.add(manifest.Service{Name: name, Type: azure.ResourceTypeService, ResourceType: containerAppResourceType})
+		case "staticwebapp":
+			p.add(manifest.Service{Name: name, Type: azure.ResourceTypeService, ResourceType: staticWebAppResourceType})
+		case "aks":
+			p.add(manifest.Service{Name: name, Type: azure.KubernetesService})
+		default:
+			log.Printf("The host %s of the azd service %s has no verification", service.Host, name)
+		}
+	}
+}
+
+// addResourceServices adds the services of the resources of the azd composability, e.g. type: db.postgres.
+func (p *Project) addResourceServices(document *projectDocument) {
+	for _, name := range sortedKeys(document.Resources) {
+		service, ok := resourceTypeServices[document.Resources[name].Type]
+		if !ok {
+			log.Printf("The type %s of the azd resource %s has no verification", document.Resources[name].Type, name)
+			continue
+		}
+
+		service.Name = name
+		p.add(service)
+	}
+}
+
+// addDatabaseServices adds the services of the databases referenced by the parameter names, e.g. redisCacheName.
+// Only the resource name parameters are matched, e.g. postgresServerName but not postgresAdminPassword or useRedis.
+func (p *Project) addDatabaseServices(parameters []string) {
+	for _, parameter := range parameters {
+		name := strings.ToLower(parameter)
+		if !strings.HasSuffix(name, resourceNameSuffix) {
+			continue
+		}
+
+		for _, database := range databaseKeywords {
+			if strings.Contains(name, database.keyword) {
+				service := database.service
+				service.Name = parameter
+				p.add(service)
+				break
+			}
+		}
+	}
+}
+
+// add adds the service unless the project already has a service of the same type.
+func (p *Project) add(service manifest.Service) {
+	for _, existing := range p.Services {
+		if existing.Type == service.Type && existing.ResourceType == service.ResourceType &&
+			existing.OperatingSystem == service.OperatingSystem && existing.PublishType == service.PublishType {
+			return
+		}
+	}
+	p.Services = append(p.Services, service)
+}
+
+// readParameterNames returns the names of the parameters of the infra module, e.g. infra/main.parameters.json.
+// A project without a parameters file has no parameters.
+func readParameterNames(dir string, document *projectDocument) ([]string, error) {
+	infraPath := document.Infra.Path
+	if infraPath == "" {
+		infraPath = defaultInfraPath
+	}
+	module := document.Infra.Module
+	if module == "" {
+		module = defaultInfraModule
+	}
+
+	path := filepath.Join(dir, infraPath, module+".parameters.json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("The azd project has no parameters file %s", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the parameters %s: %w", path, err)
+	}
+
+	parameters := struct {
+		Parameters map[string]any `json:"parameters"`
+	}{}
+	if err := json.Unmarshal(data, &parameters); err != nil {
+		return nil, fmt.Errorf("failed to parse the parameters %s: %w", path, err)
+	}
+
+	return sortedKeys(parameters.Parameters), nil
+}
+
+// loadEnvironment reads the variables of the environment, or of the default environment of .azure/config.json.
+// Nil is returned if the project has no environment.
+func loadEnvironment(dir string, name string) (*Environment, error) {
+	if name == "" {
+		data, err := os.ReadFile(filepath.Join(dir, environmentDir, configFileName))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the azd config: %w", err)
+		}
+
+		config := struct {
+			DefaultEnvironment string `json:"defaultEnvironment"`
+		}{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse the azd config: %w", err)
+		}
+
+		if config.DefaultEnvironment == "" {
+			return nil, nil
+		}
+		name = config.DefaultEnvironment
+	}
+
+	path := filepath.Join(dir, environmentDir, name, envFileName)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the azd environment %s: %w", name, err)
+	}
+	defer file.Close()
+
+	environment := &Environment{Name: name, Variables: map[string]string{}}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := parseEnvLine(scanner.Text())
+		if ok {
+			environment.Variables[key] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the azd environment %s: %w", name, err)
+	}
+
+	return environment, nil
+}
+
+// parseEnvLine parses a KEY="value" line of a .env file. The comments and the empty lines are skipped.
+func parseEnvLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", "", false
+	}
+
+	value = strings.TrimSpace(value)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	} else {
+		value = strings.Trim(value, "'")
+	}
+
+	return strings.TrimSpace(key), value, true
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
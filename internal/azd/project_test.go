@@ -0,0 +1,142 @@
+package azd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/manifest"
+)
+
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("failed to create the directory of %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestLoadProject(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"azure.yaml": `
+name: todo-python
+services:
+  web:
+    project: ./src/web
+    language: js
+    host: appservice
+  api:
+    project: ./src/api
+    language: py
+    host: containerapp
+    docker:
+      path: ./Dockerfile
+  worker:
+    project: ./src/worker
+    host: function
+resources:
+  cache:
+    type: db.redis
+`,
+		"infra/main.parameters.json": `{
+  "parameters": {
+    "environmentName": {"value": "${AZURE_ENV_NAME}"},
+    "location": {"value": "${AZURE_LOCATION}"},
+    "postgresServerName": {"value": ""},
+    "postgresAdminPassword": {"value": "$(secretOrRandomPassword)"},
+    "sqlAdminLogin": {"value": "sqladmin"},
+    "sqlServerName": {"value": ""},
+    "useMysql": {"value": false}
+  }
+}`,
+		".azure/config.json": `{"version": 1, "defaultEnvironment": "dev"}`,
+		".azure/dev/.env": `# azd environment
+AZURE_ENV_NAME="dev"
+AZURE_LOCATION="eastus2"
+AZURE_SUBSCRIPTION_ID='00000000-0000-0000-0000-000000000000'
+`,
+		".azure/prod/.env": `AZURE_LOCATION="West US 3"`,
+	})
+
+	t.Run("Test default environment", func(t *testing.T) {
+		project, err := LoadProject(dir, "")
+		if err != nil {
+			t.Fatalf("LoadProject() error = %v", err)
+		}
+
+		want := []manifest.Service{
+			{Name: "api", Type: azure.ResourceTypeService, ResourceType: containerAppResourceType},
+			{Name: "web", Type: azure.WebAppService, OperatingSystem: "linux", PublishType: "code"},
+			{Name: "cache", Type: azure.RedisService},
+			{Name: "postgresServerName", Type: azure.PostgresqlService},
+			{Name: "sqlServerName", Type: azure.SqlDatabaseService},
+		}
+		if !reflect.DeepEqual(project.Services, want) {
+			t.Errorf("LoadProject() services = %+v, want %+v", project.Services, want)
+		}
+
+		if project.Environment == nil {
+			t.Fatalf("LoadProject() environment = nil, want dev")
+		}
+		if got := project.Environment.Location(); got != "eastus2" {
+			t.Errorf("Location() = %s, want eastus2", got)
+		}
+		if got := project.Environment.SubscriptionId(); got != "00000000-0000-0000-0000-000000000000" {
+			t.Errorf("SubscriptionId() = %s, want 00000000-0000-0000-0000-000000000000", got)
+		}
+	})
+
+	t.Run("Test environment", func(t *testing.T) {
+		project, err := LoadProject(dir, "prod")
+		if err != nil {
+			t.Fatalf("LoadProject() error = %v", err)
+		}
+
+		if got := project.Environment.Location(); got != "westus3" {
+			t.Errorf("Location() = %s, want westus3", got)
+		}
+		if got := project.Environment.SubscriptionId(); got != "" {
+			t.Errorf("SubscriptionId() = %s, want empty", got)
+		}
+	})
+
+	t.Run("Test unknown environment", func(t *testing.T) {
+		if _, err := LoadProject(dir, "test"); err == nil {
+			t.Errorf("LoadProject() error = nil, want an error")
+		}
+	})
+}
+
+func TestLoadProject_WithoutInfraAndEnvironment(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"azure.yaml": `
+name: minimal
+services:
+  site:
+    host: staticwebapp
+`,
+	})
+
+	project, err := LoadProject(dir, "")
+	if err != nil {
+		t.Fatalf("LoadProject() error = %v", err)
+	}
+
+	want := []manifest.Service{{Name: "site", Type: azure.ResourceTypeService, ResourceType: staticWebAppResourceType}}
+	if !reflect.DeepEqual(project.Services, want) {
+		t.Errorf("LoadProject() services = %+v, want %+v", project.Services, want)
+	}
+
+	if project.Environment != nil {
+		t.Errorf("LoadProject() environment = %+v, want nil", project.Environment)
+	}
+}
@@ -3,17 +3,14 @@ package azure
 import (
 	"context"
 	"fmt"
-	"log"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v4"
 )
 
 type AzureAppService struct {
-	cred           *azidentity.DefaultAzureCredential
-	ctx            context.Context
-	subscriptionId string
+	session *Session
 }
 
 type AppServiceOS int
@@ -54,17 +51,65 @@ func AppServicePublishTypeFromString(publishType string) (AppServicePublishType,
 	}
 }
 
-func NewAzureAppService(cred *azidentity.DefaultAzureCredential, ctx context.Context, subscriptionId string) *AzureAppService {
+// Implement AppServicePlanTierFromSku method
+// The plan SKU can either be a pricing tier (e.g. PremiumV3) or the name of a SKU in the tier (e.g. P1v3).
+func AppServicePlanTierFromSku(sku string) (armappservice.SKUName, error) {
+	for _, tier := range armappservice.PossibleSKUNameValues() {
+		if strings.EqualFold(string(tier), sku) {
+			return tier, nil
+		}
+	}
+
+	name := strings.ToUpper(sku)
+	switch {
+	case strings.HasPrefix(name, "EP"):
+		return armappservice.SKUNameElasticPremium, nil
+	case strings.HasPrefix(name, "PC"):
+		return armappservice.SKUNamePremiumContainer, nil
+	case strings.HasPrefix(name, "P") && strings.HasSuffix(name, "V3"):
+		return armappservice.SKUNamePremiumV3, nil
+	case strings.HasPrefix(name, "P") && strings.HasSuffix(name, "V2"):
+		return armappservice.SKUNamePremiumV2, nil
+	case strings.HasPrefix(name, "P"):
+		return armappservice.SKUNamePremium, nil
+	case strings.HasPrefix(name, "I") && strings.HasSuffix(name, "V2"):
+		return armappservice.SKUNameIsolatedV2, nil
+	case strings.HasPrefix(name, "I"):
+		return armappservice.SKUNameIsolated, nil
+	case strings.HasPrefix(name, "B"):
+		return armappservice.SKUNameBasic, nil
+	case strings.HasPrefix(name, "S"):
+		return armappservice.SKUNameStandard, nil
+	case strings.HasPrefix(name, "F"):
+		return armappservice.SKUNameFree, nil
+	case strings.HasPrefix(name, "D"):
+		return armappservice.SKUNameShared, nil
+	case strings.HasPrefix(name, "Y"):
+		return armappservice.SKUNameDynamic, nil
+	default:
+		return "", fmt.Errorf("invalid App Service plan SKU: %s", sku)
+	}
+}
+
+func NewAzureAppService(session *Session) *AzureAppService {
 	return &AzureAppService{
-		cred:           cred,
-		ctx:            ctx,
-		subscriptionId: subscriptionId,
+		session: session,
 	}
 }
 
-func (a *AzureAppService) GetAppServiceLocations(locations *AzureLocationList, os AppServiceOS, publishType AppServicePublishType) (*AzureLocationList, error) {
+// GetAppServiceLocations verifies the locations support the operating system and publish type.
+// If planSku is not empty, the locations must also support the App Service plan SKU.
+func (a *AzureAppService) GetAppServiceLocations(ctx context.Context, locations *AzureLocationList, os AppServiceOS, publishType AppServicePublishType, planSku string) (*VerificationResultList, error) {
 	geoRegionOptions := armappservice.WebSiteManagementClientListGeoRegionsOptions{}
 
+	if planSku != "" {
+		tier, err := AppServicePlanTierFromSku(planSku)
+		if err != nil {
+			return nil, err
+		}
+		geoRegionOptions.SKU = to.Ptr(tier)
+	}
+
 	if os == Linux {
 		geoRegionOptions.LinuxWorkersEnabled = to.Ptr(true)
 	}
@@ -73,39 +118,59 @@ func (a *AzureAppService) GetAppServiceLocations(locations *AzureLocationList, o
 		geoRegionOptions.XenonWorkersEnabled = to.Ptr(true)
 	}
 
-	clientFactory, err := armappservice.NewClientFactory(a.subscriptionId, a.cred, nil)
+	// The geo regions are cached per combination of the options
+	name := fmt.Sprintf("%s/linux=%t/xenon=%t", planSku, geoRegionOptions.LinuxWorkersEnabled != nil, geoRegionOptions.XenonWorkersEnabled != nil)
+	displayNames, err := cached(a.session, "appservice/geoRegions", name, geoRegionsCacheTTL, func() ([]string, error) {
+		return a.getGeoRegions(ctx, &geoRegionOptions)
+	})
+	if err != nil && ctx.Err() != nil {
+		return NewCancelledResults(ctx, WebAppService, locations), nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create the app service client factory %w", err)
+		return nil, err
 	}
 
-	webSiteManagementClient := clientFactory.NewWebSiteManagementClient()
-	pager := webSiteManagementClient.NewListGeoRegionsPager(&geoRegionOptions)
+	// The API returns the location display names
+	geoRegions := make(map[string]struct{})
+	for _, displayName := range displayNames {
+		geoRegions[displayName] = struct{}{}
+	}
+
+	results := &VerificationResultList{
+		Value: []*VerificationResult{},
+	}
 
-	// Create a map of display name to location
-	// This is because the API returns the location display name
-	displayNameToLocation := make(map[string]*AzureLocation)
 	for _, location := range locations.Value {
-		displayNameToLocation[location.DisplayName] = location
+		if _, ok := geoRegions[location.DisplayName]; ok {
+			results.Value = append(results.Value, NewSupportedResult(WebAppService, location, "App Service geo region available for the requested workers"))
+		} else {
+			results.Value = append(results.Value, NewUnsupportedResult(WebAppService, location, LocationNotOfferedReason, "App Service geo region not available for the requested workers"))
+		}
 	}
 
-	appServicelocations := &AzureLocationList{
-		Value: []*AzureLocation{},
+	return results, nil
+}
+
+// getGeoRegions returns the display names of the App Service geo regions with the options.
+func (a *AzureAppService) getGeoRegions(ctx context.Context, options *armappservice.WebSiteManagementClientListGeoRegionsOptions) ([]string, error) {
+	clientFactory, err := a.session.appServiceClientFactory()
+	if err != nil {
+		return nil, err
 	}
 
+	displayNames := []string{}
+
+	pager := clientFactory.NewWebSiteManagementClient().NewListGeoRegionsPager(options)
 	for pager.More() {
-		nextResult, err := pager.NextPage(a.ctx)
+		nextResult, err := pager.NextPage(ctx)
 		if err != nil {
-			log.Printf("failed to get the app service locations %v", err)
+			return nil, fmt.Errorf("failed to get the app service locations %w", err)
 		}
 
 		for _, geoRegion := range nextResult.Value {
-			if region, ok := displayNameToLocation[*geoRegion.Properties.DisplayName]; ok {
-				appServicelocations.Value = append(appServicelocations.Value, region)
-			} else {
-				log.Printf("Location %s not found in the location list", *geoRegion.Properties.DisplayName)
-			}
+			displayNames = append(displayNames, *geoRegion.Properties.DisplayName)
 		}
 	}
 
-	return appServicelocations, nil
+	return displayNames, nil
 }
@@ -0,0 +1,25 @@
+package azure
+
+import (
+	"time"
+
+	"github.com/nickdala/azure-resource-verifier/internal/cache"
+)
+
+// The time to live of the cached responses of the Azure APIs. The offers of the regions change rarely,
+// the capabilities of the services change with their releases.
+const (
+	locationsCacheTTL          = 24 * time.Hour
+	providersCacheTTL          = 12 * time.Hour
+	geoRegionsCacheTTL         = 12 * time.Hour
+	capabilitiesCacheTTL       = 6 * time.Hour
+	skusCacheTTL               = 6 * time.Hour
+	kubernetesVersionsCacheTTL = 6 * time.Hour
+)
+
+// cached returns the cached response of an API of the subscription of the session in its cloud, or fetches and
+// caches it. The name tells the responses of the API apart, e.g. the region of the capabilities of a service or
+// the namespace of a provider. It is empty for the APIs with a single response.
+func cached[T any](session *Session, api string, name string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	return cache.Fetch(session.cache, cache.Key(CloudEndpoint(session.cloud), session.subscriptionId, api, name), ttl, fetch)
+}
@@ -0,0 +1,53 @@
+package azure
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// The retries of the requests to the Azure Resource Manager API. The delay between the tries grows exponentially,
+// unless the response tells when to retry with its Retry-After header, e.g. when the subscription is throttled.
+const (
+	maxRetries    = 5
+	retryDelay    = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// The status codes of the throttled requests and of the transient errors, which are retried
+var retryStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// isTransientError returns true if the request was throttled or failed with a transient error, even after
+// it was retried. The error doesn't tell whether the service is offered in the location.
+func isTransientError(err *azcore.ResponseError) bool {
+	return slices.Contains(retryStatusCodes, err.StatusCode)
+}
+
+// armClientOptions returns the options of the clients of the Azure Resource Manager API for the cloud.
+// The request timeout limits each try of a request, zero keeps the default of the Azure SDK.
+func armClientOptions(configuration cloud.Configuration, requestTimeout time.Duration) *arm.ClientOptions {
+	return &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Cloud: configuration,
+			Retry: policy.RetryOptions{
+				MaxRetries:    maxRetries,
+				TryTimeout:    requestTimeout,
+				RetryDelay:    retryDelay,
+				MaxRetryDelay: maxRetryDelay,
+				StatusCodes:   retryStatusCodes,
+			},
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// The clouds the Azure Resource Manager API can be called in
+const (
+	AzurePublicCloud       = "AzurePublic"
+	AzureUSGovernmentCloud = "AzureUSGovernment"
+	AzureChinaCloud        = "AzureChina"
+	CustomCloud            = "custom"
+)
+
+var Clouds = []string{AzurePublicCloud, AzureUSGovernmentCloud, AzureChinaCloud, CustomCloud}
+
+// The api version of the metadata endpoint of Azure Stack Hub and of the other custom clouds
+const cloudMetadataApiVersion = "2015-01-01"
+
+// The maximum duration of the request of the cloud metadata, which isn't retried
+const cloudMetadataTimeout = 30 * time.Second
+
+// CloudOptions selects the cloud. The endpoint of the Azure Resource Manager API is required by the custom cloud,
+// the audience and the authority host default to the ones of the metadata endpoint of the API.
+type CloudOptions struct {
+	Name          string
+	ArmEndpoint   string
+	Audience      string
+	AuthorityHost string
+}
+
+// CloudEndpoint returns the endpoint of the Azure Resource Manager API of the cloud.
+func CloudEndpoint(configuration cloud.Configuration) string {
+	return configuration.Services[cloud.ResourceManager].Endpoint
+}
+
+// resourceManagerScope returns the scope of the tokens of the Azure Resource Manager API of the cloud.
+func resourceManagerScope(configuration cloud.Configuration) string {
+	audience := configuration.Services[cloud.ResourceManager].Audience
+	return strings.TrimSuffix(audience, "/") + "/.default"
+}
+
+// NewCloudConfiguration returns the configuration of the cloud. The audience and the authority host of a custom
+// cloud that are not given are read from the metadata endpoint of its Azure Resource Manager API.
+func NewCloudConfiguration(ctx context.Context, options CloudOptions) (cloud.Configuration, error) {
+	switch options.Name {
+	case AzurePublicCloud, "":
+		return cloud.AzurePublic, nil
+	case AzureUSGovernmentCloud:
+		return cloud.AzureGovernment, nil
+	case AzureChinaCloud:
+		return cloud.AzureChina, nil
+	case CustomCloud:
+		return newCustomCloudConfiguration(ctx, options)
+	default:
+		return cloud.Configuration{}, fmt.Errorf("unknown cloud %s, expected one of %s", options.Name, strings.Join(Clouds, ", "))
+	}
+}
+
+func newCustomCloudConfiguration(ctx context.Context, options CloudOptions) (cloud.Configuration, error) {
+	if options.ArmEndpoint == "" {
+		return cloud.Configuration{}, errors.New("the custom cloud requires the endpoint of the Azure Resource Manager API")
+	}
+
+	endpoint, err := url.Parse(options.ArmEndpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return cloud.Configuration{}, fmt.Errorf("invalid Azure Resource Manager endpoint %s, expected an https url", options.ArmEndpoint)
+	}
+
+	audience, authorityHost := options.Audience, options.AuthorityHost
+	if audience == "" || authorityHost == "" {
+		metadata, err := getCloudMetadata(ctx, options.ArmEndpoint)
+		if err != nil {
+			return cloud.Configuration{}, err
+		}
+
+		audience = firstNonEmpty(audience, metadata.audience())
+		authorityHost = firstNonEmpty(authorityHost, metadata.Authentication.LoginEndpoint)
+	}
+
+	if audience == "" || authorityHost == "" {
+		return cloud.Configuration{}, fmt.Errorf("the metadata of %s has no audience or login endpoint", options.ArmEndpoint)
+	}
+
+	return cloud.Configuration{
+		ActiveDirectoryAuthorityHost: authorityHost,
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Audience: audience,
+				Endpoint: options.ArmEndpoint,
+			},
+		},
+	}, nil
+}
+
+// The metadata of the endpoints of a cloud, e.g. https://management.local.azurestack.external/metadata/endpoints
+type cloudMetadata struct {
+	Authentication struct {
+		LoginEndpoint string   `json:"loginEndpoint"`
+		Audiences     []string `json:"audiences"`
+	} `json:"authentication"`
+}
+
+// audience returns the first audience of the Azure Resource Manager API of the cloud.
+func (m *cloudMetadata) audience() string {
+	if len(m.Authentication.Audiences) == 0 {
+		return ""
+	}
+	return m.Authentication.Audiences[0]
+}
+
+// getCloudMetadata reads the metadata endpoint of the Azure Resource Manager API. The request isn't authenticated.
+func getCloudMetadata(ctx context.Context, armEndpoint string) (*cloudMetadata, error) {
+	endpoint := strings.TrimSuffix(armEndpoint, "/") + "/metadata/endpoints?api-version=" + cloudMetadataApiVersion
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: cloudMetadataTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the cloud metadata %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the cloud metadata %s: %s", endpoint, resp.Status)
+	}
+
+	metadata := &cloudMetadata{}
+	if err := json.NewDecoder(resp.Body).Decode(metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse the cloud metadata %s: %w", endpoint, err)
+	}
+	return metadata, nil
+}
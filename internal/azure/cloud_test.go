@@ -0,0 +1,91 @@
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+func TestNewCloudConfiguration(t *testing.T) {
+	tests := []struct {
+		name              string
+		options           CloudOptions
+		wantEndpoint      string
+		wantAudience      string
+		wantAuthorityHost string
+		wantErr           bool
+	}{
+		{
+			name:              "Test default",
+			options:           CloudOptions{},
+			wantEndpoint:      cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint,
+			wantAudience:      cloud.AzurePublic.Services[cloud.ResourceManager].Audience,
+			wantAuthorityHost: cloud.AzurePublic.ActiveDirectoryAuthorityHost,
+		},
+		{
+			name:              "Test US Government",
+			options:           CloudOptions{Name: AzureUSGovernmentCloud},
+			wantEndpoint:      "https://management.usgovcloudapi.net",
+			wantAudience:      "https://management.core.usgovcloudapi.net",
+			wantAuthorityHost: "https://login.microsoftonline.us/",
+		},
+		{
+			name:              "Test China",
+			options:           CloudOptions{Name: AzureChinaCloud},
+			wantEndpoint:      "https://management.chinacloudapi.cn",
+			wantAudience:      "https://management.core.chinacloudapi.cn",
+			wantAuthorityHost: "https://login.chinacloudapi.cn/",
+		},
+		{
+			name: "Test custom",
+			options: CloudOptions{
+				Name:          CustomCloud,
+				ArmEndpoint:   "https://management.local.azurestack.external",
+				Audience:      "https://management.contoso.onmicrosoft.com/0000",
+				AuthorityHost: "https://login.microsoftonline.com/",
+			},
+			wantEndpoint:      "https://management.local.azurestack.external",
+			wantAudience:      "https://management.contoso.onmicrosoft.com/0000",
+			wantAuthorityHost: "https://login.microsoftonline.com/",
+		},
+		{
+			name:    "Test custom without endpoint",
+			options: CloudOptions{Name: CustomCloud},
+			wantErr: true,
+		},
+		{
+			name:    "Test custom with http endpoint",
+			options: CloudOptions{Name: CustomCloud, ArmEndpoint: "http://management.local.azurestack.external", Audience: "audience", AuthorityHost: "https://login.microsoftonline.com/"},
+			wantErr: true,
+		},
+		{
+			name:    "Test unknown cloud",
+			options: CloudOptions{Name: "AzureGermany"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCloudConfiguration(context.Background(), tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCloudConfiguration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			resourceManager := got.Services[cloud.ResourceManager]
+			if resourceManager.Endpoint != tt.wantEndpoint {
+				t.Errorf("NewCloudConfiguration() endpoint = %s, want %s", resourceManager.Endpoint, tt.wantEndpoint)
+			}
+			if resourceManager.Audience != tt.wantAudience {
+				t.Errorf("NewCloudConfiguration() audience = %s, want %s", resourceManager.Audience, tt.wantAudience)
+			}
+			if got.ActiveDirectoryAuthorityHost != tt.wantAuthorityHost {
+				t.Errorf("NewCloudConfiguration() authority host = %s, want %s", got.ActiveDirectoryAuthorityHost, tt.wantAuthorityHost)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package azure
+
+import (
+	"sync"
+)
+
+// The default maximum number of requests of the per-location verifications in flight
+const DefaultConcurrency = 8
+
+// RequestLimiter bounds the requests of the per-location verifications in flight. The limiter is shared by the
+// sessions of a command so that the verifications of several services and subscriptions don't multiply the requests
+// to the Azure Resource Manager API.
+type RequestLimiter struct {
+	slots chan struct{}
+}
+
+// NewRequestLimiter returns the limiter of the maximum number of requests in flight.
+func NewRequestLimiter(concurrency int) *RequestLimiter {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &RequestLimiter{slots: make(chan struct{}, concurrency)}
+}
+
+// Concurrency returns the maximum number of requests of the per-location verifications in flight.
+func (l *RequestLimiter) Concurrency() int {
+	return cap(l.slots)
+}
+
+// fanOut calls fn for the indexes from 0 to count concurrently, at most Concurrency() calls at a time across
+// all the verifications sharing the limiter, and waits for all the calls. The calls store their results at their index.
+func (l *RequestLimiter) fanOut(count int, fn func(idx int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			l.slots <- struct{}{}
+			defer func() { <-l.slots }()
+
+			fn(idx)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// forEachLocation calls fn for each location with fanOut.
+func (l *RequestLimiter) forEachLocation(locations *AzureLocationList, fn func(idx int, location *AzureLocation)) {
+	l.fanOut(len(locations.Value), func(idx int) {
+		fn(idx, locations.Value[idx])
+	})
+}
@@ -0,0 +1,63 @@
+package azure
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFanOut(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		count       int
+	}{
+		{
+			name:        "Test fewer calls than the concurrency",
+			concurrency: 8,
+			count:       3,
+		},
+		{
+			name:        "Test more calls than the concurrency",
+			concurrency: 2,
+			count:       10,
+		},
+		{
+			name:        "Test invalid concurrency",
+			concurrency: 0,
+			count:       4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRequestLimiter(tt.concurrency)
+
+			var mu sync.Mutex
+			inFlight, maxInFlight := 0, 0
+			called := make([]bool, tt.count)
+
+			limiter.fanOut(tt.count, func(idx int) {
+				mu.Lock()
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+				called[idx] = true
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			})
+
+			for i, ok := range called {
+				if !ok {
+					t.Errorf("fanOut() didn't call %d", i)
+				}
+			}
+			if maxInFlight > limiter.Concurrency() {
+				t.Errorf("fanOut() had %d calls in flight, want at most %d", maxInFlight, limiter.Concurrency())
+			}
+		})
+	}
+}
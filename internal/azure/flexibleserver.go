@@ -0,0 +1,94 @@
+package azure
+
+import (
+	"context"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+// flexibleServerCapability is the part of the capabilities of a location that the PostgreSQL and MySQL
+// flexible servers have in common.
+type flexibleServerCapability struct {
+	// ZoneRedundantHaSupported is set when the capability flags zone-redundant high availability
+	ZoneRedundantHaSupported bool
+	// HaModes are the supported high availability modes, ZoneRedundant and SameZone
+	HaModes []string
+	// ServerVersions are the server versions of all the editions
+	ServerVersions []string
+}
+
+// getFlexibleServerLocations verifies the flexible servers of a service in each location of the session, with the
+// capabilities returned by getCapabilities for the location.
+func getFlexibleServerLocations(ctx context.Context, session *Session, service string, locations *AzureLocationList, getCapabilities func(location string) ([]*flexibleServerCapability, error)) *VerificationResultList {
+	// The following is used to store results from our go routine.
+	// We will merge the results after all go routines are done.
+	results := make([]*VerificationResult, len(locations.Value))
+
+	session.limiter.forEachLocation(locations, func(idx int, azureLocation *AzureLocation) {
+		log.Printf("Getting %s capabilities for location %s", service, azureLocation.DisplayName)
+		capabilities, err := getCapabilities(azureLocation.Name)
+		if err != nil {
+			if ctx.Err() != nil {
+				results[idx] = NewCancelledResult(ctx, service, azureLocation)
+			} else if azureErr, ok := err.(*azcore.ResponseError); ok && !isTransientError(azureErr) {
+				results[idx] = NewUnsupportedResult(service, azureLocation, azureErr.ErrorCode, azureErr.Error())
+			} else if ok {
+				// The request was throttled or failed with a transient error, even after it was retried
+				results[idx] = NewUnknownResult(service, azureLocation, azureErr.ErrorCode, azureErr.Error())
+			} else {
+				results[idx] = NewUnknownResult(service, azureLocation, RequestFailedReason, err.Error())
+			}
+			return
+		}
+
+		results[idx] = evaluateFlexibleServerCapabilities(service, azureLocation, capabilities)
+	})
+
+	// Remove nil values from the results.
+	return &VerificationResultList{Value: removeNilItems(results)}
+}
+
+// evaluateFlexibleServerCapabilities returns the verification result of a flexible server in a location.
+// The service can be deployed to the location when capabilities are returned for it. Both the ZoneRedundant
+// HA mode and the zone-redundant HA flag enable the high availability and zone redundancy features, and the
+// SameZone HA mode enables the same-zone high availability feature.
+func evaluateFlexibleServerCapabilities(service string, location *AzureLocation, capabilities []*flexibleServerCapability) *VerificationResult {
+	if len(capabilities) == 0 {
+		return NewUnsupportedResult(service, location, NoCapabilitiesReason, "can't deploy to this location")
+	}
+
+	result := NewSupportedResult(service, location, "capabilities returned for this location")
+	for _, capability := range capabilities {
+		if capability.ZoneRedundantHaSupported {
+			result.Features[HighAvailabilityFeature] = true
+			result.Features[ZoneRedundancyFeature] = true
+		}
+
+		for _, mode := range capability.HaModes {
+			switch mode {
+			case "ZoneRedundant":
+				result.Features[HighAvailabilityFeature] = true
+				result.Features[ZoneRedundancyFeature] = true
+			case "SameZone":
+				result.Features[SameZoneHaFeature] = true
+			}
+		}
+
+		for _, version := range capability.ServerVersions {
+			result.Features[VersionFeature(version)] = true
+		}
+	}
+
+	return result
+}
+
+func removeNilItems[T any](items []*T) []*T {
+	var result []*T
+	for _, item := range items {
+		if item != nil {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package azure
+
+import (
+	"testing"
+)
+
+func TestEvaluateFlexibleServerCapabilities(t *testing.T) {
+	location := &AzureLocation{Name: "eastus", DisplayName: "East US"}
+
+	tests := []struct {
+		name              string
+		capabilities      []*flexibleServerCapability
+		wantStatus        VerificationStatus
+		wantReason        string
+		wantHa            bool
+		wantZoneRedundant bool
+		wantSameZone      bool
+		wantVersions      []string
+	}{
+		{
+			name: "Test ZoneRedundant HA mode",
+			capabilities: []*flexibleServerCapability{
+				{HaModes: []string{"ZoneRedundant", "SameZone"}, ServerVersions: []string{"8.0.21"}},
+			},
+			wantStatus:        StatusSupported,
+			wantHa:            true,
+			wantZoneRedundant: true,
+			wantSameZone:      true,
+			wantVersions:      []string{"8.0.21"},
+		},
+		{
+			name: "Test zone-redundant HA flag",
+			capabilities: []*flexibleServerCapability{
+				{ZoneRedundantHaSupported: true, ServerVersions: []string{"15", "16"}},
+			},
+			wantStatus:        StatusSupported,
+			wantHa:            true,
+			wantZoneRedundant: true,
+			wantVersions:      []string{"15", "16"},
+		},
+		{
+			name: "Test SameZone HA mode only",
+			capabilities: []*flexibleServerCapability{
+				{HaModes: []string{"SameZone"}},
+			},
+			wantStatus:   StatusSupported,
+			wantSameZone: true,
+		},
+		{
+			name: "Test no HA mode",
+			capabilities: []*flexibleServerCapability{
+				{ServerVersions: []string{"5.7"}},
+			},
+			wantStatus:   StatusSupported,
+			wantVersions: []string{"5.7"},
+		},
+		{
+			name:       "Test no capabilities",
+			wantStatus: StatusUnsupported,
+			wantReason: NoCapabilitiesReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateFlexibleServerCapabilities(MysqlService, location, tt.capabilities)
+			if got.Status != tt.wantStatus {
+				t.Errorf("evaluateFlexibleServerCapabilities() status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if got.ReasonCode != tt.wantReason {
+				t.Errorf("evaluateFlexibleServerCapabilities() reason = %s, want %s", got.ReasonCode, tt.wantReason)
+			}
+			if got.HasFeature(HighAvailabilityFeature) != tt.wantHa {
+				t.Errorf("evaluateFlexibleServerCapabilities() high availability = %v, want %v", got.HasFeature(HighAvailabilityFeature), tt.wantHa)
+			}
+			if got.HasFeature(ZoneRedundancyFeature) != tt.wantZoneRedundant {
+				t.Errorf("evaluateFlexibleServerCapabilities() zone redundancy = %v, want %v", got.HasFeature(ZoneRedundancyFeature), tt.wantZoneRedundant)
+			}
+			if got.HasFeature(SameZoneHaFeature) != tt.wantSameZone {
+				t.Errorf("evaluateFlexibleServerCapabilities() same-zone HA = %v, want %v", got.HasFeature(SameZoneHaFeature), tt.wantSameZone)
+			}
+			for _, version := range tt.wantVersions {
+				if !got.HasFeature(VersionFeature(version)) {
+					t.Errorf("evaluateFlexibleServerCapabilities() missing version %s", version)
+				}
+			}
+		})
+	}
+}
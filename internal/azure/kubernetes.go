@@ -0,0 +1,213 @@
+package azure
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+)
+
+type AzureKubernetesService struct {
+	session *Session
+}
+
+func NewAzureKubernetesService(session *Session) *AzureKubernetesService {
+	return &AzureKubernetesService{
+		session: session,
+	}
+}
+
+// KubernetesRequirements are the prerequisites of an AKS cluster. Empty values are not verified.
+type KubernetesRequirements struct {
+	// The Kubernetes version, either a minor (e.g. 1.29) or a patch version (e.g. 1.29.2)
+	KubernetesVersion string
+	// The Virtual Machine size of the node pools, e.g. Standard_D4s_v5
+	NodeVmSize string
+	// The availability zones of the node pools. Requires NodeVmSize.
+	Zones []string
+}
+
+// GetKubernetesLocations verifies the locations meet the prerequisites of an AKS cluster.
+// The Kubernetes versions come from the AKS API and the node sizes and zones from the compute resource SKUs.
+func (a *AzureKubernetesService) GetKubernetesLocations(ctx context.Context, locations *AzureLocationList, requirements KubernetesRequirements) (*VerificationResultList, error) {
+	if len(requirements.Zones) > 0 && requirements.NodeVmSize == "" {
+		return nil, fmt.Errorf("the node VM size is required to verify the zones")
+	}
+
+	client, err := a.session.managedClustersClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// The following is used to store the versions from our go routine.
+	versions := make([]*armcontainerservice.KubernetesVersionListResult, len(locations.Value))
+	versionErrs := make([]error, len(locations.Value))
+
+	// The node VM sizes are verified while the versions are fetched
+	var nodeResults *VerificationResultList
+	var nodeErr error
+	nodeDone := make(chan struct{})
+	go func() {
+		defer close(nodeDone)
+		if requirements.NodeVmSize != "" {
+			nodeResults, nodeErr = NewAzureVirtualMachineSku(a.session).GetVirtualMachineSkuLocations(ctx, locations, requirements.NodeVmSize)
+		}
+	}()
+
+	a.session.limiter.forEachLocation(locations, func(idx int, azureLocation *AzureLocation) {
+		log.Printf("Getting Kubernetes versions for location %s", azureLocation.DisplayName)
+		versions[idx], versionErrs[idx] = cached(a.session, "aks/kubernetesVersions", azureLocation.Name, kubernetesVersionsCacheTTL, func() (*armcontainerservice.KubernetesVersionListResult, error) {
+			res, err := client.ListKubernetesVersions(ctx, azureLocation.Name, nil)
+			if err != nil {
+				return nil, err
+			}
+			return &res.KubernetesVersionListResult, nil
+		})
+	})
+
+	<-nodeDone
+
+	if nodeErr != nil {
+		return nil, nodeErr
+	}
+
+	results := &VerificationResultList{
+		Value: []*VerificationResult{},
+	}
+
+	for i, location := range locations.Value {
+		if versionErrs[i] != nil {
+			if ctx.Err() != nil {
+				results.Value = append(results.Value, NewCancelledResult(ctx, KubernetesService, location))
+			} else if azureErr, ok := versionErrs[i].(*azcore.ResponseError); ok && !isTransientError(azureErr) {
+				results.Value = append(results.Value, NewUnsupportedResult(KubernetesService, location, azureErr.ErrorCode, azureErr.Error()))
+			} else if ok {
+				// The request was throttled or failed with a transient error, even after it was retried
+				results.Value = append(results.Value, NewUnknownResult(KubernetesService, location, azureErr.ErrorCode, azureErr.Error()))
+			} else {
+				results.Value = append(results.Value, NewUnknownResult(KubernetesService, location, RequestFailedReason, versionErrs[i].Error()))
+			}
+			continue
+		}
+
+		var nodeResult *VerificationResult
+		if nodeResults != nil {
+			nodeResult = nodeResults.Value[i]
+		}
+
+		results.Value = append(results.Value, evaluateKubernetes(location, versions[i], nodeResult, requirements))
+	}
+
+	return results, nil
+}
+
+// evaluateKubernetes verifies the Kubernetes versions and the node VM size of a location meet the requirements.
+// The latest generally available version is reported when no version is required.
+func evaluateKubernetes(location *AzureLocation, versions *armcontainerservice.KubernetesVersionListResult, nodeResult *VerificationResult, requirements KubernetesRequirements) *VerificationResult {
+	available := make([]string, 0, len(versions.Values))
+	var version, latest *armcontainerservice.KubernetesVersion
+	for _, v := range versions.Values {
+		if v.Version == nil {
+			continue
+		}
+		available = append(available, *v.Version)
+		if requirements.KubernetesVersion != "" && kubernetesVersionMatches(v, requirements.KubernetesVersion) {
+			version = v
+		}
+		if latest == nil || isLaterKubernetesVersion(v, latest) {
+			latest = v
+		}
+	}
+
+	if len(available) == 0 {
+		return NewUnsupportedResult(KubernetesService, location, NoCapabilitiesReason, "no Kubernetes versions in this location")
+	}
+
+	if requirements.KubernetesVersion == "" {
+		version = latest
+	} else if version == nil {
+		return NewUnsupportedResult(KubernetesService, location, VersionNotSupportedReason, fmt.Sprintf("Kubernetes %s is not available, available versions: %s", requirements.KubernetesVersion, strings.Join(available, ", ")))
+	}
+
+	result := NewSupportedResult(KubernetesService, location, fmt.Sprintf("Kubernetes %s", *version.Version))
+	for _, v := range available {
+		result.Features[VersionFeature(v)] = true
+	}
+
+	if isPreviewKubernetesVersion(version) {
+		result.Status = StatusDegraded
+		result.ReasonCode = PreviewVersionReason
+		result.Evidence = fmt.Sprintf("Kubernetes %s is in preview", *version.Version)
+	}
+
+	if nodeResult == nil {
+		return result
+	}
+
+	// The node VM size decides if the cluster can be deployed and to which zones.
+	// A node VM size that couldn't be verified, e.g. when the verification was cancelled, leaves the cluster unknown.
+	if nodeResult.Status == StatusUnknown {
+		return NewUnknownResult(KubernetesService, location, nodeResult.ReasonCode, nodeResult.Evidence)
+	}
+	if !nodeResult.IsDeployable() {
+		return NewUnsupportedResult(KubernetesService, location, nodeResult.ReasonCode, nodeResult.Evidence)
+	}
+
+	if nodeResult.Status == StatusDegraded && result.Status == StatusSupported {
+		result.Status = StatusDegraded
+		result.ReasonCode = nodeResult.ReasonCode
+		result.Evidence = nodeResult.Evidence
+	}
+
+	result.Zones = nodeResult.Zones
+	result.Features[ZoneRedundancyFeature] = nodeResult.HasFeature(ZoneRedundancyFeature)
+
+	for _, zone := range requirements.Zones {
+		if !slices.Contains(nodeResult.Zones, zone) {
+			return NewUnsupportedResult(KubernetesService, location, ZonesNotSupportedReason, fmt.Sprintf("%s is not available in zone %s", requirements.NodeVmSize, zone))
+		}
+	}
+
+	return result
+}
+
+// kubernetesVersionMatches returns true if the requested version is the minor version or one of its patch versions.
+func kubernetesVersionMatches(version *armcontainerservice.KubernetesVersion, requested string) bool {
+	if *version.Version == requested {
+		return true
+	}
+	_, ok := version.PatchVersions[requested]
+	return ok
+}
+
+func isPreviewKubernetesVersion(version *armcontainerservice.KubernetesVersion) bool {
+	return version.IsPreview != nil && *version.IsPreview
+}
+
+// isLaterKubernetesVersion returns true if the version is later than the other, a generally available version
+// being later than any preview version.
+func isLaterKubernetesVersion(version, other *armcontainerservice.KubernetesVersion) bool {
+	if isPreviewKubernetesVersion(version) != isPreviewKubernetesVersion(other) {
+		return isPreviewKubernetesVersion(other)
+	}
+	return compareKubernetesVersions(*version.Version, *other.Version) > 0
+}
+
+// compareKubernetesVersions compares the numeric parts of two Kubernetes versions, e.g. 1.29 < 1.30.
+func compareKubernetesVersions(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numberA, _ := strconv.Atoi(partsA[i])
+		numberB, _ := strconv.Atoi(partsB[i])
+		if numberA != numberB {
+			return cmp.Compare(numberA, numberB)
+		}
+	}
+	return cmp.Compare(len(partsA), len(partsB))
+}
@@ -0,0 +1,101 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+)
+
+func TestEvaluateKubernetes(t *testing.T) {
+	location := &AzureLocation{Name: "eastus2", DisplayName: "East US 2"}
+
+	versions := &armcontainerservice.KubernetesVersionListResult{
+		Values: []*armcontainerservice.KubernetesVersion{
+			{Version: to.Ptr("1.28"), PatchVersions: map[string]*armcontainerservice.KubernetesPatchVersion{"1.28.9": {}}},
+			{Version: to.Ptr("1.29"), PatchVersions: map[string]*armcontainerservice.KubernetesPatchVersion{"1.29.2": {}, "1.29.4": {}}},
+			{Version: to.Ptr("1.30"), IsPreview: to.Ptr(true), PatchVersions: map[string]*armcontainerservice.KubernetesPatchVersion{"1.30.0": {}}},
+		},
+	}
+
+	nodeResult := NewSupportedResult(VirtualMachineSkuService, location, "Standard_D4s_v5 is offered in this location")
+	nodeResult.Zones = []string{"1", "2"}
+	nodeResult.Features[ZoneRedundancyFeature] = true
+
+	restrictedNodeResult := NewUnsupportedResult(VirtualMachineSkuService, location, "NotAvailableForSubscription", "Standard_D4s_v5 is restricted in this location for the subscription")
+
+	cancelledNodeResult := NewUnknownResult(VirtualMachineSkuService, location, CancelledReason, "the verification was cancelled: received interrupt")
+
+	tests := []struct {
+		name         string
+		requirements KubernetesRequirements
+		nodeResult   *VerificationResult
+		wantStatus   VerificationStatus
+		wantReason   string
+		wantEvidence string
+	}{
+		{
+			name:         "Test latest generally available version",
+			wantStatus:   StatusSupported,
+			wantEvidence: "Kubernetes 1.29",
+		},
+		{
+			name:         "Test patch version",
+			requirements: KubernetesRequirements{KubernetesVersion: "1.29.2"},
+			wantStatus:   StatusSupported,
+		},
+		{
+			name:         "Test preview version",
+			requirements: KubernetesRequirements{KubernetesVersion: "1.30"},
+			wantStatus:   StatusDegraded,
+			wantReason:   PreviewVersionReason,
+		},
+		{
+			name:         "Test version not offered",
+			requirements: KubernetesRequirements{KubernetesVersion: "1.25"},
+			wantStatus:   StatusUnsupported,
+			wantReason:   VersionNotSupportedReason,
+		},
+		{
+			name:         "Test node size in zones",
+			requirements: KubernetesRequirements{NodeVmSize: "Standard_D4s_v5", Zones: []string{"1", "2"}},
+			nodeResult:   nodeResult,
+			wantStatus:   StatusSupported,
+		},
+		{
+			name:         "Test node size not in zone",
+			requirements: KubernetesRequirements{NodeVmSize: "Standard_D4s_v5", Zones: []string{"1", "2", "3"}},
+			nodeResult:   nodeResult,
+			wantStatus:   StatusUnsupported,
+			wantReason:   ZonesNotSupportedReason,
+		},
+		{
+			name:         "Test restricted node size",
+			requirements: KubernetesRequirements{NodeVmSize: "Standard_D4s_v5"},
+			nodeResult:   restrictedNodeResult,
+			wantStatus:   StatusUnsupported,
+			wantReason:   "NotAvailableForSubscription",
+		},
+		{
+			name:         "Test cancelled node size",
+			requirements: KubernetesRequirements{NodeVmSize: "Standard_D4s_v5"},
+			nodeResult:   cancelledNodeResult,
+			wantStatus:   StatusUnknown,
+			wantReason:   CancelledReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateKubernetes(location, versions, tt.nodeResult, tt.requirements)
+			if got.Status != tt.wantStatus {
+				t.Errorf("evaluateKubernetes() status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if got.ReasonCode != tt.wantReason {
+				t.Errorf("evaluateKubernetes() reason = %s, want %s", got.ReasonCode, tt.wantReason)
+			}
+			if tt.wantEvidence != "" && got.Evidence != tt.wantEvidence {
+				t.Errorf("evaluateKubernetes() evidence = %s, want %s", got.Evidence, tt.wantEvidence)
+			}
+		})
+	}
+}
@@ -1,8 +1,15 @@
 package azure
 
+import "strings"
+
 type AzureLocation struct {
-	Name        string
-	DisplayName string
+	Name        string `json:"name" yaml:"name"`
+	DisplayName string `json:"displayName" yaml:"displayName"`
+}
+
+// NormalizeLocation returns the name of a location given by its display name, e.g. East US 2 is eastus2.
+func NormalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
 }
 
 type AzureLocationList struct {
@@ -76,3 +76,34 @@ func TestAzureLocationList_Intersection(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		want     string
+	}{
+		{
+			name:     "Test location name",
+			location: "eastus2",
+			want:     "eastus2",
+		},
+		{
+			name:     "Test location display name",
+			location: "East US 2",
+			want:     "eastus2",
+		},
+		{
+			name:     "Test global",
+			location: "Global",
+			want:     "global",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeLocation(tt.location); got != tt.want {
+				t.Errorf("NormalizeLocation() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,37 +5,38 @@ import (
 	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 )
 
 type AzureLocationLocator struct {
-	cred           *azidentity.DefaultAzureCredential
-	ctx            context.Context
-	subscriptionId string
+	session *Session
 }
 
-func NewAzureLocationLocator(cred *azidentity.DefaultAzureCredential, ctx context.Context, subscriptionId string) *AzureLocationLocator {
+func NewAzureLocationLocator(session *Session) *AzureLocationLocator {
 	return &AzureLocationLocator{
-		cred:           cred,
-		ctx:            ctx,
-		subscriptionId: subscriptionId,
+		session: session,
 	}
 }
 
-func (a *AzureLocationLocator) GetLocations() (*AzureLocationList, error) {
-	clientFactory, err := armsubscriptions.NewClientFactory(a.cred, nil)
+func (a *AzureLocationLocator) GetLocations(ctx context.Context) (*AzureLocationList, error) {
+	return cached(a.session, "locations", "", locationsCacheTTL, func() (*AzureLocationList, error) {
+		return a.getLocations(ctx)
+	})
+}
+
+func (a *AzureLocationLocator) getLocations(ctx context.Context) (*AzureLocationList, error) {
+	clientFactory, err := a.session.subscriptionsClientFactory()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %v", err)
+		return nil, err
 	}
 
 	locations := &AzureLocationList{
 		Value: []*AzureLocation{},
 	}
 
-	pager := clientFactory.NewClient().NewListLocationsPager(a.subscriptionId, &armsubscriptions.ClientListLocationsOptions{IncludeExtendedLocations: to.Ptr(false)})
+	pager := clientFactory.NewClient().NewListLocationsPager(a.session.subscriptionId, &armsubscriptions.ClientListLocationsOptions{IncludeExtendedLocations: to.Ptr(false)})
 	for pager.More() {
-		page, err := pager.NextPage(a.ctx)
+		page, err := pager.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to advance page: %v", err)
 		}
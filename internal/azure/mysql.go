@@ -0,0 +1,54 @@
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/mysql/armmysqlflexibleservers"
+)
+
+type AzureMysqlFlexibleServer struct {
+	session *Session
+}
+
+func NewAzureMysqlFlexibleServer(session *Session) *AzureMysqlFlexibleServer {
+	return &AzureMysqlFlexibleServer{
+		session: session,
+	}
+}
+
+func (a *AzureMysqlFlexibleServer) GetMysqlLocations(ctx context.Context, locations *AzureLocationList) (*VerificationResultList, error) {
+	client, err := a.session.mysqlLocationBasedCapabilitiesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return getFlexibleServerLocations(ctx, a.session, MysqlService, locations, func(location string) ([]*flexibleServerCapability, error) {
+		capabilities, err := cached(a.session, "mysql/capabilities", location, capabilitiesCacheTTL, func() ([]*armmysqlflexibleservers.CapabilityProperties, error) {
+			// The first page has the capabilities of the location
+			page, err := client.NewListPager(location, nil).NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return page.Value, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]*flexibleServerCapability, 0, len(capabilities))
+		for _, capability := range capabilities {
+			flexibleServer := &flexibleServerCapability{
+				HaModes: toValues(capability.SupportedHAMode),
+			}
+			for _, edition := range capability.SupportedFlexibleServerEditions {
+				for _, version := range edition.SupportedServerVersions {
+					if version.Name != nil {
+						flexibleServer.ServerVersions = append(flexibleServer.ServerVersions, *version.Name)
+					}
+				}
+			}
+			result = append(result, flexibleServer)
+		}
+		return result, nil
+	}), nil
+}
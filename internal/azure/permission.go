@@ -0,0 +1,135 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const permissionsApiVersion = "2022-04-01"
+
+// The read actions every service check needs: the locations of the subscription, the resource providers
+// and the Azure Policy assignments evaluated against the results.
+var commonReadActions = []string{
+	"Microsoft.Resources/subscriptions/read",
+	"Microsoft.Resources/subscriptions/locations/read",
+	"Microsoft.Resources/subscriptions/providers/read",
+	"Microsoft.Authorization/policyAssignments/read",
+	"Microsoft.Authorization/policyDefinitions/read",
+	"Microsoft.Authorization/policySetDefinitions/read",
+}
+
+// The read actions of the APIs each service check calls, besides the common read actions, including the quota usages.
+// The checks of the resource types offered by a provider, e.g. redis, only read the providers.
+var serviceReadActions = map[string][]string{
+	RedisService:             {},
+	PostgresqlService:        {"Microsoft.DBforPostgreSQL/locations/capabilities/read", "Microsoft.DBforPostgreSQL/locations/resourceType/usages/read"},
+	MysqlService:             {"Microsoft.DBforMySQL/locations/capabilities/read"},
+	SqlDatabaseService:       {"Microsoft.Sql/locations/capabilities/read"},
+	VirtualMachineSkuService: {"Microsoft.Compute/skus/read", "Microsoft.Compute/locations/usages/read"},
+	KubernetesService: {
+		"Microsoft.ContainerService/locations/kubernetesVersions/read",
+		"Microsoft.Compute/skus/read",
+		"Microsoft.Compute/locations/usages/read",
+		"Microsoft.Network/locations/usages/read",
+	},
+	ResourceTypeService: {},
+	WebAppService:       {"Microsoft.Web/geoRegions/read"},
+}
+
+// Services returns the services that can be verified, in a stable order.
+func Services() []string {
+	return []string{RedisService, PostgresqlService, MysqlService, SqlDatabaseService, VirtualMachineSkuService, KubernetesService, ResourceTypeService, WebAppService}
+}
+
+// RequiredReadActions returns the read actions a service check needs, including the common read actions.
+func RequiredReadActions(service string) ([]string, error) {
+	actions, ok := serviceReadActions[service]
+	if !ok {
+		return nil, fmt.Errorf("unknown service %s", service)
+	}
+
+	return append(append([]string{}, commonReadActions...), actions...), nil
+}
+
+// AzurePermission is a set of actions the principal can and can't perform, from one of its role assignments.
+type AzurePermission struct {
+	Actions    []string `json:"actions"`
+	NotActions []string `json:"notActions"`
+}
+
+type AzurePermissionList struct {
+	Value []*AzurePermission
+}
+
+// IsAllowed returns true if a permission allows the action and doesn't exclude it with its not actions.
+// The actions can have wildcards, e.g. */read or Microsoft.Compute/*.
+func (p *AzurePermissionList) IsAllowed(action string) bool {
+	for _, permission := range p.Value {
+		if matchesAnyAction(permission.Actions, action) && !matchesAnyAction(permission.NotActions, action) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyAction(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		if matchesAction(pattern, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAction matches an action against an action pattern, case insensitively. A * in the pattern
+// matches any sequence of characters, including the / separators.
+func matchesAction(pattern string, action string) bool {
+	pattern = strings.ToLower(pattern)
+	action = strings.ToLower(action)
+
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == action
+	}
+
+	if !strings.HasPrefix(action, parts[0]) {
+		return false
+	}
+	action = action[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(action, part)
+		if idx < 0 {
+			return false
+		}
+		action = action[idx+len(part):]
+	}
+
+	return strings.HasSuffix(action, parts[len(parts)-1])
+}
+
+type AzureAuthorization struct {
+	session *Session
+}
+
+func NewAzureAuthorization(session *Session) *AzureAuthorization {
+	return &AzureAuthorization{
+		session: session,
+	}
+}
+
+// GetPermissions returns the permissions of the principal of the credential in the subscription,
+// from all its role assignments, including the assignments inherited from the management groups.
+func (a *AzureAuthorization) GetPermissions(ctx context.Context) (*AzurePermissionList, error) {
+	path := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/permissions", url.PathEscape(a.session.subscriptionId))
+	query := url.Values{"api-version": []string{permissionsApiVersion}}
+
+	permissions, err := armList[*AzurePermission](ctx, a.session, path, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the permissions in the subscription %s %w", a.session.subscriptionId, err)
+	}
+
+	return &AzurePermissionList{Value: permissions}, nil
+}
@@ -0,0 +1,111 @@
+package azure
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAzurePermissionList_IsAllowed(t *testing.T) {
+	reader := &AzurePermission{Actions: []string{"*/read"}}
+	contributor := &AzurePermission{
+		Actions:    []string{"*"},
+		NotActions: []string{"Microsoft.Authorization/*/Delete", "Microsoft.Authorization/*/Write"},
+	}
+	postgres := &AzurePermission{
+		Actions:    []string{"Microsoft.DBforPostgreSQL/*"},
+		NotActions: []string{"Microsoft.DBforPostgreSQL/locations/*"},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []*AzurePermission
+		action      string
+		want        bool
+	}{
+		{
+			name:        "Test reader",
+			permissions: []*AzurePermission{reader},
+			action:      "Microsoft.DBforPostgreSQL/locations/capabilities/read",
+			want:        true,
+		},
+		{
+			name:        "Test reader case insensitive",
+			permissions: []*AzurePermission{{Actions: []string{"microsoft.compute/SKUS/read"}}},
+			action:      "Microsoft.Compute/skus/read",
+			want:        true,
+		},
+		{
+			name:        "Test contributor",
+			permissions: []*AzurePermission{contributor},
+			action:      "Microsoft.Authorization/policyAssignments/read",
+			want:        true,
+		},
+		{
+			name:        "Test contributor not action",
+			permissions: []*AzurePermission{contributor},
+			action:      "Microsoft.Authorization/policyAssignments/write",
+			want:        false,
+		},
+		{
+			name:        "Test not action of the permission",
+			permissions: []*AzurePermission{postgres},
+			action:      "Microsoft.DBforPostgreSQL/locations/capabilities/read",
+			want:        false,
+		},
+		{
+			name:        "Test not action of another permission",
+			permissions: []*AzurePermission{postgres, reader},
+			action:      "Microsoft.DBforPostgreSQL/locations/capabilities/read",
+			want:        true,
+		},
+		{
+			name:        "Test other provider",
+			permissions: []*AzurePermission{postgres},
+			action:      "Microsoft.DBforMySQL/locations/capabilities/read",
+			want:        false,
+		},
+		{
+			name:        "Test no permissions",
+			permissions: []*AzurePermission{},
+			action:      "Microsoft.Resources/subscriptions/read",
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions := &AzurePermissionList{Value: tt.permissions}
+			if got := permissions.IsAllowed(tt.action); got != tt.want {
+				t.Errorf("IsAllowed(%s) = %v, want %v", tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredReadActionsOfQuotas(t *testing.T) {
+	// The read actions of the APIs listing the quota usages of each provider
+	quotaReadActions := map[string]string{
+		ComputeQuotaProvider:    "Microsoft.Compute/locations/usages/read",
+		NetworkQuotaProvider:    "Microsoft.Network/locations/usages/read",
+		PostgresqlQuotaProvider: "Microsoft.DBforPostgreSQL/locations/resourceType/usages/read",
+	}
+
+	for _, service := range Services() {
+		t.Run(service, func(t *testing.T) {
+			actions, err := RequiredReadActions(service)
+			if err != nil {
+				t.Fatalf("RequiredReadActions() error = %v", err)
+			}
+
+			for _, provider := range QuotaProvidersOf(ServiceQuotas(service)) {
+				action, ok := quotaReadActions[provider]
+				if !ok {
+					t.Fatalf("no read action for the %s quota usages", provider)
+				}
+				if !slices.Contains(actions, action) {
+					t.Errorf("RequiredReadActions() = %v, want %s for the %s quota usages", actions, action, provider)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,452 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	policyAssignmentsApiVersion = "2022-06-01"
+	policyDefinitionsApiVersion = "2021-06-01"
+)
+
+// The built-in policy definitions evaluated by the verifier
+const (
+	AllowedLocationsPolicy          = "e56962a6-4747-49cd-b67b-bf8b01975c4c"
+	AllowedResourceTypesPolicy      = "a08ec900-254a-4555-9bf5-e42af04b5c5c"
+	NotAllowedResourceTypesPolicy   = "6c112d4e-5bc7-47ae-a041-ea2d9dccd749"
+	AllowedVirtualMachineSkusPolicy = "cccc23c7-8427-4f53-ad12-b6a63eb452b3"
+)
+
+// The parameters of the built-in policy definitions
+const (
+	allowedLocationsParameter        = "listOfAllowedLocations"
+	allowedResourceTypesParameter    = "listOfResourceTypesAllowed"
+	notAllowedResourceTypesParameter = "listOfResourceTypesNotAllowed"
+	allowedSkusParameter             = "listOfAllowedSKUs"
+	effectParameter                  = "effect"
+)
+
+// The effect of the policy definitions that denies the deployments
+const denyEffect = "Deny"
+
+// An initiative parameter referenced by a parameter of a member definition, e.g. [parameters('listOfAllowedLocations')]
+var parameterReferencePattern = regexp.MustCompile(`^\[parameters\('([^']+)'\)\]$`)
+
+// The policy assignments that are not enforced are only audited
+const doNotEnforceMode = "DoNotEnforce"
+
+// The resource types a service is deployed with
+var serviceResourceTypes = map[string][]string{
+	RedisService:             {"Microsoft.Cache/Redis"},
+	PostgresqlService:        {"Microsoft.DBforPostgreSQL/flexibleServers"},
+	MysqlService:             {"Microsoft.DBforMySQL/flexibleServers"},
+	SqlDatabaseService:       {"Microsoft.Sql/servers", "Microsoft.Sql/servers/databases"},
+	VirtualMachineSkuService: {"Microsoft.Compute/virtualMachines"},
+	KubernetesService:        {"Microsoft.ContainerService/managedClusters", "Microsoft.Compute/virtualMachineScaleSets"},
+	WebAppService:            {"Microsoft.Web/serverFarms", "Microsoft.Web/sites"},
+}
+
+// ServiceResourceTypes returns the resource types a service is deployed with.
+// The service of a resource type, e.g. Microsoft.App/managedEnvironments, is deployed with the type itself.
+func ServiceResourceTypes(service string) []string {
+	if resourceTypes, ok := serviceResourceTypes[service]; ok {
+		return resourceTypes
+	}
+
+	if _, _, err := ParseResourceType(service); err == nil {
+		return []string{service}
+	}
+
+	return nil
+}
+
+// PolicyAssignment is an assignment of a policy definition, as returned by the Azure Policy API.
+type PolicyAssignment struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		DisplayName        string                     `json:"displayName"`
+		PolicyDefinitionId string                     `json:"policyDefinitionId"`
+		EnforcementMode    string                     `json:"enforcementMode"`
+		NotScopes          []string                   `json:"notScopes"`
+		Parameters         map[string]policyParameter `json:"parameters"`
+	} `json:"properties"`
+
+	// The evaluated built-in definitions of the assignment, set by resolvePolicies
+	policies []*assignedPolicy
+}
+
+type policyParameter struct {
+	Value any `json:"value"`
+}
+
+// policyDefinition is a policy definition or a policy set definition (initiative), as returned by the Azure Policy API.
+type policyDefinition struct {
+	Properties struct {
+		Parameters map[string]policyDefinitionParameter `json:"parameters"`
+		// The member definitions of a policy set definition
+		PolicyDefinitions []policyDefinitionReference `json:"policyDefinitions"`
+	} `json:"properties"`
+}
+
+type policyDefinitionParameter struct {
+	DefaultValue any `json:"defaultValue"`
+}
+
+type policyDefinitionReference struct {
+	PolicyDefinitionId string                     `json:"policyDefinitionId"`
+	Parameters         map[string]policyParameter `json:"parameters"`
+}
+
+// parameterValues returns the values of the parameters of the definition: the given values, otherwise the default values.
+func (d *policyDefinition) parameterValues(values map[string]any) map[string]any {
+	result := map[string]any{}
+	for name, parameter := range d.Properties.Parameters {
+		if parameter.DefaultValue != nil {
+			result[name] = parameter.DefaultValue
+		}
+	}
+	for name, value := range values {
+		result[name] = value
+	}
+	return result
+}
+
+// assignedPolicy is a built-in definition evaluated by the verifier, assigned directly or as a member of an initiative,
+// with the values of its parameters.
+type assignedPolicy struct {
+	definitionName string
+	parameters     map[string]any
+}
+
+// DefinitionName returns the name of the assigned policy definition, e.g. the guid of a built-in definition.
+func (p *PolicyAssignment) DefinitionName() string {
+	return path.Base(p.Properties.PolicyDefinitionId)
+}
+
+// DisplayName returns the display name of the assignment, or its name if it has none.
+func (p *PolicyAssignment) DisplayName() string {
+	if p.Properties.DisplayName != "" {
+		return p.Properties.DisplayName
+	}
+	return p.Name
+}
+
+// assignedValues returns the values of the parameters set by the assignment.
+func (p *PolicyAssignment) assignedValues() map[string]any {
+	values := map[string]any{}
+	for name, parameter := range p.Properties.Parameters {
+		values[name] = parameter.Value
+	}
+	return values
+}
+
+// assignedPolicies returns the evaluated built-in definitions of the assignment. An assignment that isn't resolved,
+// e.g. when its definition can't be read, is evaluated with the parameters it sets if it assigns a built-in definition.
+func (p *PolicyAssignment) assignedPolicies() []*assignedPolicy {
+	if p.policies != nil {
+		return p.policies
+	}
+
+	if !isEvaluatedPolicy(p.DefinitionName()) {
+		return nil
+	}
+	return []*assignedPolicy{{definitionName: p.DefinitionName(), parameters: p.assignedValues()}}
+}
+
+// isInitiative returns true if the assignment assigns a policy set definition.
+func (p *PolicyAssignment) isInitiative() bool {
+	return strings.Contains(strings.ToLower(p.Properties.PolicyDefinitionId), "/policysetdefinitions/")
+}
+
+// resolvePolicies resolves the evaluated built-in definitions of the assignment: the assigned definition, or the
+// members of the assigned initiative. The parameters left out by the assignment take the default values of the
+// definitions. getDefinition returns the policy or policy set definition of an id.
+func (p *PolicyAssignment) resolvePolicies(getDefinition func(id string) (*policyDefinition, error)) error {
+	policies := []*assignedPolicy{}
+
+	if !p.isInitiative() {
+		if isEvaluatedPolicy(p.DefinitionName()) {
+			definition, err := getDefinition(p.Properties.PolicyDefinitionId)
+			if err != nil {
+				return err
+			}
+			policies = append(policies, &assignedPolicy{definitionName: p.DefinitionName(), parameters: definition.parameterValues(p.assignedValues())})
+		}
+
+		p.policies = policies
+		return nil
+	}
+
+	initiative, err := getDefinition(p.Properties.PolicyDefinitionId)
+	if err != nil {
+		return err
+	}
+
+	// The parameters of the members reference the parameters of the initiative
+	initiativeValues := initiative.parameterValues(p.assignedValues())
+	for _, member := range initiative.Properties.PolicyDefinitions {
+		definitionName := path.Base(member.PolicyDefinitionId)
+		if !isEvaluatedPolicy(definitionName) {
+			continue
+		}
+
+		definition, err := getDefinition(member.PolicyDefinitionId)
+		if err != nil {
+			return err
+		}
+
+		values := map[string]any{}
+		for name, parameter := range member.Parameters {
+			value, ok := resolveParameterValue(parameter.Value, initiativeValues)
+			if !ok {
+				log.Printf("The parameter %s of %s in the initiative of the policy assignment %s can't be evaluated", name, definitionName, p.DisplayName())
+				continue
+			}
+			values[name] = value
+		}
+		policies = append(policies, &assignedPolicy{definitionName: definitionName, parameters: definition.parameterValues(values)})
+	}
+
+	p.policies = policies
+	return nil
+}
+
+// resolveParameterValue returns the value of a parameter of an initiative member: a literal value, or the value of the
+// initiative parameter it references. False is returned for the other policy expressions.
+func resolveParameterValue(value any, initiativeValues map[string]any) (any, bool) {
+	expression, ok := value.(string)
+	if !ok || !strings.HasPrefix(expression, "[") || strings.HasPrefix(expression, "[[") {
+		return value, true
+	}
+
+	match := parameterReferencePattern.FindStringSubmatch(expression)
+	if match == nil {
+		return nil, false
+	}
+
+	referenced, ok := initiativeValues[match[1]]
+	return referenced, ok
+}
+
+// isEvaluatedPolicy returns true if the definition is one of the built-in definitions evaluated by the verifier.
+func isEvaluatedPolicy(definitionName string) bool {
+	switch definitionName {
+	case AllowedLocationsPolicy, AllowedResourceTypesPolicy, NotAllowedResourceTypesPolicy, AllowedVirtualMachineSkusPolicy:
+		return true
+	default:
+		return false
+	}
+}
+
+// parameterValues returns the values of a list parameter of the policy.
+// False is returned if the parameter has no value.
+func (p *assignedPolicy) parameterValues(name string) ([]string, bool) {
+	list, ok := p.parameters[name].([]any)
+	if !ok {
+		return nil, false
+	}
+
+	values := []string{}
+	for _, value := range list {
+		if s, ok := value.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values, true
+}
+
+// denies returns true if the effect of the policy denies the deployments. The definitions without an effect
+// parameter, e.g. allowed locations, always deny.
+func (p *assignedPolicy) denies() bool {
+	effect, ok := p.parameters[effectParameter].(string)
+	return !ok || strings.EqualFold(effect, denyEffect)
+}
+
+// appliesTo returns true if the assignment is enforced on the scope, i.e. the scope isn't excluded.
+func (p *PolicyAssignment) appliesTo(scope string) bool {
+	if strings.EqualFold(p.Properties.EnforcementMode, doNotEnforceMode) {
+		return false
+	}
+
+	for _, notScope := range p.Properties.NotScopes {
+		if isWithinScope(scope, notScope) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isWithinScope returns true if the scope is the parent scope or one of its child scopes, e.g. a resource group
+// of a subscription, but not a sibling scope whose name starts with the name of the parent, e.g. rg-prod of rg.
+// The scopes are compared case-insensitively.
+func isWithinScope(scope string, parent string) bool {
+	scope, parent = strings.ToLower(scope), strings.TrimSuffix(strings.ToLower(parent), "/")
+	return scope == parent || strings.HasPrefix(scope, parent+"/")
+}
+
+type AzurePolicy struct {
+	session *Session
+}
+
+func NewAzurePolicy(session *Session) *AzurePolicy {
+	return &AzurePolicy{
+		session: session,
+	}
+}
+
+// PolicyScope returns the scope the policy assignments are read for: the management group if given,
+// otherwise the resource group if given, otherwise the subscription.
+func (a *AzurePolicy) PolicyScope(resourceGroup string, managementGroup string) string {
+	if managementGroup != "" {
+		return fmt.Sprintf("/providers/Microsoft.Management/managementGroups/%s", url.PathEscape(managementGroup))
+	}
+
+	scope := fmt.Sprintf("/subscriptions/%s", url.PathEscape(a.session.subscriptionId))
+	if resourceGroup != "" {
+		scope += fmt.Sprintf("/resourceGroups/%s", url.PathEscape(resourceGroup))
+	}
+	return scope
+}
+
+// GetPolicyAssignments returns the policy assignments in effect at the scope, including the inherited assignments.
+// The evaluated built-in definitions of the assignments are resolved, including the members of the initiatives.
+// An assignment whose definitions can't be read is evaluated with the parameters it sets only.
+func (a *AzurePolicy) GetPolicyAssignments(ctx context.Context, scope string) ([]*PolicyAssignment, error) {
+	log.Printf("Getting the policy assignments of %s", scope)
+
+	query := url.Values{
+		"api-version": []string{policyAssignmentsApiVersion},
+		"$filter":     []string{"atScope()"},
+	}
+
+	assignments, err := armList[*PolicyAssignment](ctx, a.session, scope+"/providers/Microsoft.Authorization/policyAssignments", query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the policy assignments of %s %w", scope, err)
+	}
+
+	// The definitions are shared by the assignments, e.g. the built-in definitions
+	definitions := map[string]*policyDefinition{}
+	getDefinition := func(id string) (*policyDefinition, error) {
+		key := strings.ToLower(id)
+		if definition, ok := definitions[key]; ok {
+			return definition, nil
+		}
+
+		definition := &policyDefinition{}
+		if err := armGet(ctx, a.session, id, policyDefinitionsApiVersion, definition); err != nil {
+			return nil, err
+		}
+		definitions[key] = definition
+		return definition, nil
+	}
+
+	for _, assignment := range assignments {
+		if err := assignment.resolvePolicies(getDefinition); err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			if assignment.isInitiative() {
+				log.Printf("The initiative of the policy assignment %s was not evaluated: %s", assignment.DisplayName(), err)
+			} else {
+				log.Printf("The default parameters of the policy assignment %s were not evaluated: %s", assignment.DisplayName(), err)
+			}
+		}
+	}
+
+	return assignments, nil
+}
+
+// ApplyPolicies marks the deployable locations that are denied by the policy assignments as unsupported.
+// The built-in allowed locations, allowed and not allowed resource types and allowed virtual machine SKUs
+// definitions are evaluated, assigned directly or in an initiative. The virtual machine sizes are the sizes the service is deployed with, if any.
+// The scope is the subscription or the resource group the service is deployed to, the excluded scopes of the assignments are evaluated against it.
+func (list *VerificationResultList) ApplyPolicies(assignments []*PolicyAssignment, scope string, vmSizes ...string) {
+	for _, result := range list.Value {
+		if !result.IsDeployable() {
+			continue
+		}
+
+		if evidence, blocked := evaluateAssignments(assignments, scope, result, vmSizes); blocked {
+			result.Status = StatusUnsupported
+			result.ReasonCode = BlockedByPolicyReason
+			result.Evidence = evidence
+		}
+	}
+}
+
+// evaluateAssignments returns true and the evidence if one of the assignments enforced on the scope denies the service
+// in the location of the result.
+func evaluateAssignments(assignments []*PolicyAssignment, scope string, result *VerificationResult, vmSizes []string) (string, bool) {
+	for _, assignment := range assignments {
+		if !assignment.appliesTo(scope) {
+			continue
+		}
+
+		for _, policy := range assignment.assignedPolicies() {
+			if evidence, blocked := evaluatePolicy(policy, result, vmSizes); blocked {
+				return fmt.Sprintf("blocked by policy %s: %s", assignment.DisplayName(), evidence), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// evaluatePolicy returns true and the evidence if the policy denies the service in the location of the result.
+func evaluatePolicy(policy *assignedPolicy, result *VerificationResult, vmSizes []string) (string, bool) {
+	if !policy.denies() {
+		return "", false
+	}
+
+	containsFold := func(values []string, value string) bool {
+		return slices.ContainsFunc(values, func(v string) bool {
+			return strings.EqualFold(v, value)
+		})
+	}
+
+	switch policy.definitionName {
+	case AllowedLocationsPolicy:
+		// The built-in definition doesn't apply to the global resources, e.g. Front Door or DNS zones
+		if strings.EqualFold(result.Location.Name, globalLocation) {
+			break
+		}
+		if allowed, ok := policy.parameterValues(allowedLocationsParameter); ok && !containsFold(allowed, result.Location.Name) {
+			return fmt.Sprintf("%s is not an allowed location", result.Location.Name), true
+		}
+	case AllowedResourceTypesPolicy:
+		if allowed, ok := policy.parameterValues(allowedResourceTypesParameter); ok {
+			for _, resourceType := range ServiceResourceTypes(result.Service) {
+				if !containsFold(allowed, resourceType) {
+					return fmt.Sprintf("%s is not an allowed resource type", resourceType), true
+				}
+			}
+		}
+	case NotAllowedResourceTypesPolicy:
+		if notAllowed, ok := policy.parameterValues(notAllowedResourceTypesParameter); ok {
+			for _, resourceType := range ServiceResourceTypes(result.Service) {
+				if containsFold(notAllowed, resourceType) {
+					return fmt.Sprintf("%s is not an allowed resource type", resourceType), true
+				}
+			}
+		}
+	case AllowedVirtualMachineSkusPolicy:
+		if allowed, ok := policy.parameterValues(allowedSkusParameter); ok {
+			for _, size := range vmSizes {
+				if size != "" && !containsFold(allowed, size) {
+					return fmt.Sprintf("%s is not an allowed virtual machine size", size), true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,260 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestApplyPolicies(t *testing.T) {
+	var assignments []*PolicyAssignment
+	err := json.Unmarshal([]byte(`[
+		{
+			"name": "allowed-locations",
+			"properties": {
+				"displayName": "Allowed locations",
+				"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/e56962a6-4747-49cd-b67b-bf8b01975c4c",
+				"parameters": { "listOfAllowedLocations": { "value": ["eastus2", "westus3"] } }
+			}
+		},
+		{
+			"name": "not-allowed-types",
+			"properties": {
+				"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/6c112d4e-5bc7-47ae-a041-ea2d9dccd749",
+				"parameters": { "listOfResourceTypesNotAllowed": { "value": ["Microsoft.Cache/Redis"] } }
+			}
+		},
+		{
+			"name": "allowed-skus",
+			"properties": {
+				"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/cccc23c7-8427-4f53-ad12-b6a63eb452b3",
+				"parameters": { "listOfAllowedSKUs": { "value": ["standard_d4s_v5"] } }
+			}
+		},
+		{
+			"name": "audit-only",
+			"properties": {
+				"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/e56962a6-4747-49cd-b67b-bf8b01975c4c",
+				"enforcementMode": "DoNotEnforce",
+				"parameters": { "listOfAllowedLocations": { "value": [] } }
+			}
+		},
+		{
+			"name": "excluded",
+			"properties": {
+				"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/a08ec900-254a-4555-9bf5-e42af04b5c5c",
+				"notScopes": ["/subscriptions/00000000-0000-0000-0000-000000000000"],
+				"parameters": { "listOfResourceTypesAllowed": { "value": [] } }
+			}
+		}
+	]`), &assignments)
+	if err != nil {
+		t.Fatalf("failed to decode the policy assignments: %v", err)
+	}
+
+	scope := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg"
+	eastus2 := &AzureLocation{Name: "eastus2", DisplayName: "East US 2"}
+	brazilsouth := &AzureLocation{Name: "brazilsouth", DisplayName: "Brazil South"}
+	global := &AzureLocation{Name: "global", DisplayName: "global"}
+
+	tests := []struct {
+		name       string
+		result     *VerificationResult
+		vmSizes    []string
+		wantStatus VerificationStatus
+		wantReason string
+	}{
+		{
+			name:       "Test allowed location",
+			result:     NewSupportedResult(PostgresqlService, eastus2, ""),
+			wantStatus: StatusSupported,
+		},
+		{
+			name:       "Test location not allowed",
+			result:     NewSupportedResult(PostgresqlService, brazilsouth, ""),
+			wantStatus: StatusUnsupported,
+			wantReason: BlockedByPolicyReason,
+		},
+		{
+			name:       "Test global location",
+			result:     NewSupportedResult(PostgresqlService, global, ""),
+			wantStatus: StatusSupported,
+		},
+		{
+			name:       "Test resource type not allowed",
+			result:     NewSupportedResult(RedisService, eastus2, ""),
+			wantStatus: StatusUnsupported,
+			wantReason: BlockedByPolicyReason,
+		},
+		{
+			name:       "Test allowed virtual machine size",
+			result:     NewSupportedResult(VirtualMachineSkuService, eastus2, ""),
+			vmSizes:    []string{"Standard_D4s_v5"},
+			wantStatus: StatusSupported,
+		},
+		{
+			name:       "Test virtual machine size not allowed",
+			result:     NewSupportedResult(KubernetesService, eastus2, ""),
+			vmSizes:    []string{"Standard_E4s_v5"},
+			wantStatus: StatusUnsupported,
+			wantReason: BlockedByPolicyReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := &VerificationResultList{Value: []*VerificationResult{tt.result}}
+			list.ApplyPolicies(assignments, scope, tt.vmSizes...)
+			if tt.result.Status != tt.wantStatus {
+				t.Errorf("ApplyPolicies() status = %s, want %s (%s)", tt.result.Status, tt.wantStatus, tt.result.Evidence)
+			}
+			if tt.result.ReasonCode != tt.wantReason {
+				t.Errorf("ApplyPolicies() reason = %s, want %s", tt.result.ReasonCode, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestResolvePolicies(t *testing.T) {
+	var definitions map[string]*policyDefinition
+	err := json.Unmarshal([]byte(`{
+		"/providers/Microsoft.Authorization/policyDefinitions/e56962a6-4747-49cd-b67b-bf8b01975c4c": {
+			"properties": { "parameters": { "listOfAllowedLocations": {} } }
+		},
+		"/providers/Microsoft.Authorization/policyDefinitions/6c112d4e-5bc7-47ae-a041-ea2d9dccd749": {
+			"properties": { "parameters": {
+				"listOfResourceTypesNotAllowed": { "defaultValue": ["Microsoft.Cache/Redis"] },
+				"effect": { "defaultValue": "Deny" }
+			} }
+		},
+		"/providers/Microsoft.Management/managementGroups/contoso/providers/Microsoft.Authorization/policySetDefinitions/landing-zone": {
+			"properties": {
+				"parameters": { "allowedLocations": { "defaultValue": ["eastus2"] } },
+				"policyDefinitions": [
+					{
+						"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/e56962a6-4747-49cd-b67b-bf8b01975c4c",
+						"parameters": { "listOfAllowedLocations": { "value": "[parameters('allowedLocations')]" } }
+					},
+					{
+						"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/6c112d4e-5bc7-47ae-a041-ea2d9dccd749",
+						"parameters": { "effect": { "value": "Audit" } }
+					}
+				]
+			}
+		}
+	}`), &definitions)
+	if err != nil {
+		t.Fatalf("failed to decode the policy definitions: %v", err)
+	}
+
+	getDefinition := func(id string) (*policyDefinition, error) {
+		if definition, ok := definitions[id]; ok {
+			return definition, nil
+		}
+		return nil, fmt.Errorf("policy definition %s not found", id)
+	}
+
+	scope := "/subscriptions/00000000-0000-0000-0000-000000000000"
+	eastus2 := &AzureLocation{Name: "eastus2", DisplayName: "East US 2"}
+	westus3 := &AzureLocation{Name: "westus3", DisplayName: "West US 3"}
+
+	tests := []struct {
+		name       string
+		assignment string
+		result     *VerificationResult
+		wantErr    bool
+		wantStatus VerificationStatus
+	}{
+		{
+			name:       "Test initiative parameter default value",
+			assignment: `{"properties": {"policyDefinitionId": "/providers/Microsoft.Management/managementGroups/contoso/providers/Microsoft.Authorization/policySetDefinitions/landing-zone"}}`,
+			result:     NewSupportedResult(PostgresqlService, westus3, ""),
+			wantStatus: StatusUnsupported,
+		},
+		{
+			name:       "Test initiative parameter value",
+			assignment: `{"properties": {"policyDefinitionId": "/providers/Microsoft.Management/managementGroups/contoso/providers/Microsoft.Authorization/policySetDefinitions/landing-zone", "parameters": {"allowedLocations": {"value": ["westus3"]}}}}`,
+			result:     NewSupportedResult(PostgresqlService, westus3, ""),
+			wantStatus: StatusSupported,
+		},
+		{
+			name:       "Test initiative member audit effect",
+			assignment: `{"properties": {"policyDefinitionId": "/providers/Microsoft.Management/managementGroups/contoso/providers/Microsoft.Authorization/policySetDefinitions/landing-zone"}}`,
+			result:     NewSupportedResult(RedisService, eastus2, ""),
+			wantStatus: StatusSupported,
+		},
+		{
+			name:       "Test definition default value",
+			assignment: `{"properties": {"policyDefinitionId": "/providers/Microsoft.Authorization/policyDefinitions/6c112d4e-5bc7-47ae-a041-ea2d9dccd749"}}`,
+			result:     NewSupportedResult(RedisService, eastus2, ""),
+			wantStatus: StatusUnsupported,
+		},
+		{
+			name:       "Test initiative not found",
+			assignment: `{"properties": {"policyDefinitionId": "/providers/Microsoft.Authorization/policySetDefinitions/unknown"}}`,
+			result:     NewSupportedResult(PostgresqlService, westus3, ""),
+			wantErr:    true,
+			wantStatus: StatusSupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignment := &PolicyAssignment{}
+			if err := json.Unmarshal([]byte(tt.assignment), assignment); err != nil {
+				t.Fatalf("failed to decode the policy assignment: %v", err)
+			}
+			if err := assignment.resolvePolicies(getDefinition); (err != nil) != tt.wantErr {
+				t.Fatalf("resolvePolicies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			list := &VerificationResultList{Value: []*VerificationResult{tt.result}}
+			list.ApplyPolicies([]*PolicyAssignment{assignment}, scope)
+			if tt.result.Status != tt.wantStatus {
+				t.Errorf("ApplyPolicies() status = %s, want %s (%s)", tt.result.Status, tt.wantStatus, tt.result.Evidence)
+			}
+		})
+	}
+}
+
+func TestPolicyAssignmentAppliesTo(t *testing.T) {
+	assignment := &PolicyAssignment{}
+	assignment.Properties.NotScopes = []string{"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg"}
+
+	tests := []struct {
+		name  string
+		scope string
+		want  bool
+	}{
+		{
+			name:  "Test excluded scope",
+			scope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg",
+			want:  false,
+		},
+		{
+			name:  "Test excluded scope in another case",
+			scope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/RG",
+			want:  false,
+		},
+		{
+			name:  "Test child of the excluded scope",
+			scope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.Cache/Redis/cache",
+			want:  false,
+		},
+		{
+			name:  "Test sibling of the excluded scope",
+			scope: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-prod",
+			want:  true,
+		},
+		{
+			name:  "Test parent of the excluded scope",
+			scope: "/subscriptions/00000000-0000-0000-0000-000000000000",
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := assignment.appliesTo(tt.scope); got != tt.want {
+				t.Errorf("PolicyAssignment.appliesTo() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
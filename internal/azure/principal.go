@@ -0,0 +1,104 @@
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// The types of principal
+const (
+	UserPrincipalType        = "user"
+	ApplicationPrincipalType = "app"
+)
+
+// AzurePrincipal is the identity a credential authenticates as, read from the claims of its access token.
+type AzurePrincipal struct {
+	TenantId      string `json:"tenantId" yaml:"tenantId"`
+	ObjectId      string `json:"objectId" yaml:"objectId"`
+	Name          string `json:"name" yaml:"name"`
+	Type          string `json:"type" yaml:"type"`
+	ApplicationId string `json:"applicationId,omitempty" yaml:"applicationId,omitempty"`
+}
+
+// The claims of an access token used to find the principal
+type tokenClaims struct {
+	TenantId          string `json:"tid"`
+	ObjectId          string `json:"oid"`
+	IdentityType      string `json:"idtyp"`
+	Upn               string `json:"upn"`
+	UniqueName        string `json:"unique_name"`
+	PreferredUsername string `json:"preferred_username"`
+	AppDisplayName    string `json:"app_displayname"`
+	AppId             string `json:"appid"`
+}
+
+// GetPrincipal requests an access token of the Azure Resource Manager API of the cloud with the credential and
+// returns its principal.
+func GetPrincipal(ctx context.Context, cred azcore.TokenCredential, configuration cloud.Configuration) (*AzurePrincipal, error) {
+	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{resourceManagerScope(configuration)}})
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrincipal(token.Token)
+}
+
+// parsePrincipal reads the principal from the claims of an access token. The signature isn't verified,
+// the token is only used to report who the credential authenticates as.
+func parsePrincipal(token string) (*AzurePrincipal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("the access token is not a JSON web token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the access token: %w", err)
+	}
+
+	claims := &tokenClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("failed to parse the claims of the access token: %w", err)
+	}
+
+	principal := &AzurePrincipal{
+		TenantId: claims.TenantId,
+		ObjectId: claims.ObjectId,
+		Type:     claims.IdentityType,
+	}
+
+	// The tokens of the users have a user name, the tokens of the applications and managed identities have none
+	name := firstNonEmpty(claims.Upn, claims.PreferredUsername, claims.UniqueName)
+	if principal.Type == "" {
+		principal.Type = UserPrincipalType
+		if name == "" {
+			principal.Type = ApplicationPrincipalType
+		}
+	}
+
+	if principal.Type == ApplicationPrincipalType {
+		principal.ApplicationId = claims.AppId
+		name = firstNonEmpty(claims.AppDisplayName, claims.AppId)
+	}
+	principal.Name = firstNonEmpty(name, claims.ObjectId)
+
+	return principal, nil
+}
+
+// firstNonEmpty returns the first value that isn't empty.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package azure
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestParsePrincipal(t *testing.T) {
+	token := func(claims string) string {
+		return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    *AzurePrincipal
+		wantErr bool
+	}{
+		{
+			name:  "Test user",
+			token: token(`{"tid": "tenant", "oid": "1111", "upn": "dev@contoso.com", "appid": "04b07795-8ddb-461a-bbee-02f9e1bf7b46"}`),
+			want:  &AzurePrincipal{TenantId: "tenant", ObjectId: "1111", Name: "dev@contoso.com", Type: UserPrincipalType},
+		},
+		{
+			name:  "Test service principal",
+			token: token(`{"tid": "tenant", "oid": "2222", "idtyp": "app", "appid": "3333", "app_displayname": "ci-pipeline"}`),
+			want:  &AzurePrincipal{TenantId: "tenant", ObjectId: "2222", Name: "ci-pipeline", Type: ApplicationPrincipalType, ApplicationId: "3333"},
+		},
+		{
+			name:  "Test managed identity",
+			token: token(`{"tid": "tenant", "oid": "4444", "appid": "5555"}`),
+			want:  &AzurePrincipal{TenantId: "tenant", ObjectId: "4444", Name: "5555", Type: ApplicationPrincipalType, ApplicationId: "5555"},
+		},
+		{
+			name:    "Test not a token",
+			token:   "opaque",
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid claims",
+			token:   token(`not json`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePrincipal(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePrincipal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePrincipal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
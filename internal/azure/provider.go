@@ -0,0 +1,203 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// The location of the resource types that are not deployed to a region, e.g. Microsoft.Network/dnszones
+const globalLocation = "global"
+
+type AzureResourceProvider struct {
+	session *Session
+}
+
+func NewAzureResourceProvider(session *Session) *AzureResourceProvider {
+	return &AzureResourceProvider{
+		session: session,
+	}
+}
+
+// ParseResourceType splits a resource type, e.g. Microsoft.App/managedEnvironments, into the provider namespace
+// and the type. Nested types, e.g. Microsoft.Sql/servers/databases, are supported.
+func ParseResourceType(resourceType string) (string, string, error) {
+	namespace, typeName, ok := strings.Cut(resourceType, "/")
+	if !ok || namespace == "" || typeName == "" {
+		return "", "", fmt.Errorf("invalid resource type %s: expected <Namespace>/<resourceType>, e.g. Microsoft.App/managedEnvironments", resourceType)
+	}
+	return namespace, typeName, nil
+}
+
+// GetResourceTypeLocations verifies the resource type, e.g. Microsoft.App/managedEnvironments, is offered in the locations.
+// If an API version is given, it must be offered as well. The API versions and the availability zones of the resource type
+// are reported per location.
+func (a *AzureResourceProvider) GetResourceTypeLocations(ctx context.Context, locations *AzureLocationList, resourceType string, apiVersion string) (*VerificationResultList, error) {
+	namespace, typeName, err := ParseResourceType(resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := a.session.offeredProvider(ctx, namespace)
+	if err != nil && ctx.Err() != nil {
+		return NewCancelledResults(ctx, resourceType, locations), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := &VerificationResultList{
+		Value: []*VerificationResult{},
+	}
+
+	providerType := lookupResourceType(provider, typeName)
+	if providerType == nil {
+		for _, location := range locations.Value {
+			results.Value = append(results.Value, NewUnsupportedResult(resourceType, location, ResourceTypeNotFoundReason, fmt.Sprintf("%s is not a resource type of the %s provider", resourceType, namespace)))
+		}
+		return results, nil
+	}
+
+	for _, location := range locations.Value {
+		result := evaluateResourceType(location, resourceType, providerType)
+		if apiVersion != "" && result.IsDeployable() && !slices.ContainsFunc(result.ApiVersions, func(v string) bool { return strings.EqualFold(v, apiVersion) }) {
+			result = NewUnsupportedResult(resourceType, location, ApiVersionNotSupportedReason, fmt.Sprintf("API version %s of %s is not offered, the latest is %s", apiVersion, resourceType, LatestApiVersion(result.ApiVersions)))
+		}
+		results.Value = append(results.Value, result)
+	}
+
+	return results, nil
+}
+
+// evaluateResourceType verifies the resource type is offered in the location.
+func evaluateResourceType(location *AzureLocation, resourceType string, providerType *armresources.ProviderResourceType) *VerificationResult {
+	// The resource types without locations, e.g. Microsoft.Authorization/roleAssignments, aren't regional
+	offered := len(providerType.Locations) == 0 || slices.ContainsFunc(providerType.Locations, func(value *string) bool {
+		return value != nil && (isLocation(*value, location) || strings.EqualFold(*value, globalLocation))
+	})
+
+	if !offered {
+		return NewUnsupportedResult(resourceType, location, LocationNotOfferedReason, fmt.Sprintf("%s is not offered in this location", resourceType))
+	}
+
+	result := NewSupportedResult(resourceType, location, fmt.Sprintf("%s is offered in this location", resourceType))
+	result.ApiVersions = toValues(providerType.APIVersions)
+
+	for _, mapping := range providerType.ZoneMappings {
+		if mapping.Location != nil && isLocation(*mapping.Location, location) {
+			result.Zones = append(result.Zones, toValues(mapping.Zones)...)
+		}
+	}
+	slices.Sort(result.Zones)
+	result.Features[ZoneRedundancyFeature] = len(result.Zones) > 1
+
+	return result
+}
+
+// isLocation returns true if the value is the name (eastus2) or the display name (East US 2) of the location.
+// The resource providers report the display names, but not always with the same case.
+func isLocation(value string, location *AzureLocation) bool {
+	return strings.EqualFold(value, location.DisplayName) ||
+		strings.EqualFold(strings.ReplaceAll(value, " ", ""), location.Name)
+}
+
+// LatestApiVersion returns the most recent stable API version, or the most recent preview version
+// if the resource type has no stable version. The versions are sorted from the most recent by the provider.
+func LatestApiVersion(apiVersions []string) string {
+	for _, version := range apiVersions {
+		if !strings.Contains(version, "preview") {
+			return version
+		}
+	}
+	if len(apiVersions) > 0 {
+		return apiVersions[0]
+	}
+	return ""
+}
+
+// getProvider returns the resource provider, e.g. Microsoft.Cache, with its resource types.
+func getProvider(ctx context.Context, session *Session, namespace string) (*armresources.Provider, error) {
+	clientFactory, err := session.resourcesClientFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := clientFactory.NewProvidersClient().Get(ctx, namespace, &armresources.ProvidersClientGetOptions{Expand: nil})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the %s provider %w", namespace, err)
+	}
+
+	return &res.Provider, nil
+}
+
+// getOfferedProvider returns the resource provider with the resource types it offers, from the cache if it hasn't expired.
+// The registration state of the provider is read with getProvider, as it changes when the provider is registered.
+func getOfferedProvider(ctx context.Context, session *Session, namespace string) (*armresources.Provider, error) {
+	return cached(session, "providers", namespace, providersCacheTTL, func() (*armresources.Provider, error) {
+		return getProvider(ctx, session, namespace)
+	})
+}
+
+// getResourceType returns the resource type of the provider, e.g. "Redis" for the Microsoft.Cache provider.
+func getResourceType(provider *armresources.Provider, resourceTypeName string) (*armresources.ProviderResourceType, error) {
+	if provider.ResourceTypes == nil {
+		return nil, fmt.Errorf("failed to get the provider resource types")
+	}
+
+	resourceType := lookupResourceType(provider, resourceTypeName)
+	if resourceType == nil || resourceType.Locations == nil {
+		return nil, fmt.Errorf("no %s locations found", resourceTypeName)
+	}
+
+	return resourceType, nil
+}
+
+// lookupResourceType returns the resource type of the provider, or nil if the provider doesn't have the type.
+func lookupResourceType(provider *armresources.Provider, resourceTypeName string) *armresources.ProviderResourceType {
+	for _, resourceType := range provider.ResourceTypes {
+		if resourceType.ResourceType != nil && strings.EqualFold(*resourceType.ResourceType, resourceTypeName) {
+			return resourceType
+		}
+	}
+
+	return nil
+}
+
+// zonesByLocation returns the availability zones of the resource type keyed by the location display name.
+func zonesByLocation(resourceType *armresources.ProviderResourceType) map[string][]string {
+	zones := make(map[string][]string)
+	for _, mapping := range resourceType.ZoneMappings {
+		if mapping.Location == nil {
+			continue
+		}
+		for _, zone := range mapping.Zones {
+			zones[*mapping.Location] = append(zones[*mapping.Location], *zone)
+		}
+	}
+	return zones
+}
+
+// toSet returns the set of the non-nil values.
+func toSet(values []*string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, value := range values {
+		if value != nil {
+			set[*value] = struct{}{}
+		}
+	}
+	return set
+}
+
+// toValues returns the non-nil values.
+func toValues(values []*string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			result = append(result, *value)
+		}
+	}
+	return result
+}
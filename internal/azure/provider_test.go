@@ -0,0 +1,72 @@
+package azure
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+func TestEvaluateResourceType(t *testing.T) {
+	providerType := &armresources.ProviderResourceType{
+		ResourceType: to.Ptr("managedEnvironments"),
+		Locations:    []*string{to.Ptr("East US 2"), to.Ptr("west europe")},
+		APIVersions:  []*string{to.Ptr("2024-10-02-preview"), to.Ptr("2024-03-01"), to.Ptr("2023-05-01")},
+		ZoneMappings: []*armresources.ZoneMapping{
+			{Location: to.Ptr("East US 2"), Zones: []*string{to.Ptr("3"), to.Ptr("1"), to.Ptr("2")}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		location     *AzureLocation
+		providerType *armresources.ProviderResourceType
+		wantStatus   VerificationStatus
+		wantZones    []string
+	}{
+		{
+			name:         "Test location with zones",
+			location:     &AzureLocation{Name: "eastus2", DisplayName: "East US 2"},
+			providerType: providerType,
+			wantStatus:   StatusSupported,
+			wantZones:    []string{"1", "2", "3"},
+		},
+		{
+			name:         "Test location in another case without zones",
+			location:     &AzureLocation{Name: "westeurope", DisplayName: "West Europe"},
+			providerType: providerType,
+			wantStatus:   StatusSupported,
+		},
+		{
+			name:         "Test location not offered",
+			location:     &AzureLocation{Name: "brazilsouth", DisplayName: "Brazil South"},
+			providerType: providerType,
+			wantStatus:   StatusUnsupported,
+		},
+		{
+			name:     "Test global resource type",
+			location: &AzureLocation{Name: "brazilsouth", DisplayName: "Brazil South"},
+			providerType: &armresources.ProviderResourceType{
+				ResourceType: to.Ptr("dnszones"),
+				Locations:    []*string{to.Ptr("global")},
+			},
+			wantStatus: StatusSupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateResourceType(tt.location, "Microsoft.App/managedEnvironments", tt.providerType)
+			if got.Status != tt.wantStatus {
+				t.Errorf("evaluateResourceType() status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if !slices.Equal(got.Zones, tt.wantZones) {
+				t.Errorf("evaluateResourceType() zones = %v, want %v", got.Zones, tt.wantZones)
+			}
+		})
+	}
+
+	if got := LatestApiVersion(toValues(providerType.APIVersions)); got != "2024-03-01" {
+		t.Errorf("LatestApiVersion() = %s, want 2024-03-01", got)
+	}
+}
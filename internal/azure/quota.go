@@ -0,0 +1,313 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// The providers of the quota usages
+const (
+	ComputeQuotaProvider    = "compute"
+	NetworkQuotaProvider    = "network"
+	PostgresqlQuotaProvider = "postgresql"
+)
+
+// QuotaProviders lists every supported quota provider.
+var QuotaProviders = []string{ComputeQuotaProvider, NetworkQuotaProvider, PostgresqlQuotaProvider}
+
+// CoresQuotaName is the compute quota of the total regional vCPUs
+const CoresQuotaName = "cores"
+
+const postgresqlQuotaUsagesApiVersion = "2024-08-01"
+
+// QuotaUsage is the usage of a quota of the subscription in a location.
+type QuotaUsage struct {
+	Location    string `json:"location" yaml:"location"`
+	Provider    string `json:"provider" yaml:"provider"`
+	Name        string `json:"name" yaml:"name"`
+	DisplayName string `json:"displayName" yaml:"displayName"`
+	Current     int64  `json:"current" yaml:"current"`
+	Limit       int64  `json:"limit" yaml:"limit"`
+	Unit        string `json:"unit,omitempty" yaml:"unit,omitempty"`
+}
+
+// Remaining returns the headroom of the quota.
+func (u *QuotaUsage) Remaining() int64 {
+	return u.Limit - u.Current
+}
+
+// QuotaName identifies a quota of a provider. An empty name stands for all the quotas of the provider.
+type QuotaName struct {
+	Provider string
+	Name     string
+}
+
+// The quotas reported with the verification results of a service
+var serviceQuotas = map[string][]QuotaName{
+	VirtualMachineSkuService: {{ComputeQuotaProvider, CoresQuotaName}},
+	KubernetesService: {
+		{ComputeQuotaProvider, CoresQuotaName},
+		{NetworkQuotaProvider, "PublicIPAddresses"},
+		{NetworkQuotaProvider, "LoadBalancers"},
+	},
+	PostgresqlService: {{PostgresqlQuotaProvider, ""}},
+}
+
+// ServiceQuotas returns the quotas reported with the verification results of a service.
+func ServiceQuotas(service string) []QuotaName {
+	return serviceQuotas[service]
+}
+
+// QuotaProvidersOf returns the providers of the quotas.
+func QuotaProvidersOf(quotas []QuotaName) []string {
+	providers := []string{}
+	for _, quota := range quotas {
+		if !slices.Contains(providers, quota.Provider) {
+			providers = append(providers, quota.Provider)
+		}
+	}
+	return providers
+}
+
+// The quota usages of the PostgreSQL flexible servers, as returned by the API
+type postgresqlQuotaUsageList struct {
+	Value []*struct {
+		Name *struct {
+			Value          string `json:"value"`
+			LocalizedValue string `json:"localizedValue"`
+		} `json:"name"`
+		CurrentValue int64  `json:"currentValue"`
+		Limit        int64  `json:"limit"`
+		Unit         string `json:"unit"`
+	} `json:"value"`
+}
+
+type AzureQuota struct {
+	session *Session
+}
+
+func NewAzureQuota(session *Session) *AzureQuota {
+	return &AzureQuota{
+		session: session,
+	}
+}
+
+// GetQuotaUsages returns the quota usages of the providers keyed by the location name, and the errors of the
+// locations whose usages can't be read, also keyed by the location name. The quota of those locations isn't verified.
+func (a *AzureQuota) GetQuotaUsages(ctx context.Context, locations *AzureLocationList, providers []string) (map[string][]*QuotaUsage, map[string]error, error) {
+	for _, provider := range providers {
+		if !slices.Contains(QuotaProviders, provider) {
+			return nil, nil, fmt.Errorf("invalid quota provider %s: supported providers are %s", provider, strings.Join(QuotaProviders, ", "))
+		}
+	}
+
+	// The following is used to store the usages from our go routine.
+	usages := make([][]*QuotaUsage, len(locations.Value)*len(providers))
+	usageErrs := make([]error, len(usages))
+
+	a.session.limiter.fanOut(len(usages), func(idx int) {
+		azureLocation, provider := locations.Value[idx/len(providers)], providers[idx%len(providers)]
+		log.Printf("Getting %s quota usages for location %s", provider, azureLocation.DisplayName)
+		locationUsages, err := a.getQuotaUsages(ctx, azureLocation, provider)
+		if err != nil && ctx.Err() != nil {
+			usageErrs[idx] = fmt.Errorf("the verification was cancelled: %w", context.Cause(ctx))
+			return
+		}
+		if err != nil {
+			log.Printf("Error getting %s quota usages for location %s: %s", provider, azureLocation.DisplayName, err)
+			usageErrs[idx] = fmt.Errorf("error getting the %s quota usages: %w", provider, err)
+			return
+		}
+		usages[idx] = locationUsages
+	})
+
+	usagesByLocation := make(map[string][]*QuotaUsage)
+	errsByLocation := make(map[string]error)
+	for i, location := range locations.Value {
+		var errs []error
+		for j := range providers {
+			usagesByLocation[location.Name] = append(usagesByLocation[location.Name], usages[i*len(providers)+j]...)
+			if err := usageErrs[i*len(providers)+j]; err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			errsByLocation[location.Name] = errors.Join(errs...)
+		}
+	}
+
+	return usagesByLocation, errsByLocation, nil
+}
+
+func (a *AzureQuota) getQuotaUsages(ctx context.Context, location *AzureLocation, provider string) ([]*QuotaUsage, error) {
+	switch provider {
+	case ComputeQuotaProvider:
+		return a.getComputeUsages(ctx, location)
+	case NetworkQuotaProvider:
+		return a.getNetworkUsages(ctx, location)
+	case PostgresqlQuotaProvider:
+		return a.getPostgresqlUsages(ctx, location)
+	default:
+		return nil, fmt.Errorf("unknown quota provider %s", provider)
+	}
+}
+
+func (a *AzureQuota) getComputeUsages(ctx context.Context, location *AzureLocation) ([]*QuotaUsage, error) {
+	client, err := a.session.computeUsageClient()
+	if err != nil {
+		return nil, err
+	}
+
+	usages := []*QuotaUsage{}
+	pager := client.NewListPager(location.Name, nil)
+	for pager.More() {
+		nextResult, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, usage := range nextResult.Value {
+			if usage.Name == nil || usage.CurrentValue == nil || usage.Limit == nil {
+				continue
+			}
+			usages = append(usages, &QuotaUsage{
+				Location:    location.Name,
+				Provider:    ComputeQuotaProvider,
+				Name:        valueOrDefault(usage.Name.Value, ""),
+				DisplayName: valueOrDefault(usage.Name.LocalizedValue, ""),
+				Current:     int64(*usage.CurrentValue),
+				Limit:       *usage.Limit,
+				Unit:        valueOrDefault(usage.Unit, ""),
+			})
+		}
+	}
+
+	return usages, nil
+}
+
+func (a *AzureQuota) getNetworkUsages(ctx context.Context, location *AzureLocation) ([]*QuotaUsage, error) {
+	client, err := a.session.networkUsagesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	usages := []*QuotaUsage{}
+	pager := client.NewListPager(location.Name, nil)
+	for pager.More() {
+		nextResult, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, usage := range nextResult.Value {
+			if usage.Name == nil || usage.CurrentValue == nil || usage.Limit == nil {
+				continue
+			}
+			quotaUsage := &QuotaUsage{
+				Location:    location.Name,
+				Provider:    NetworkQuotaProvider,
+				Name:        valueOrDefault(usage.Name.Value, ""),
+				DisplayName: valueOrDefault(usage.Name.LocalizedValue, ""),
+				Current:     *usage.CurrentValue,
+				Limit:       *usage.Limit,
+			}
+			if usage.Unit != nil {
+				quotaUsage.Unit = string(*usage.Unit)
+			}
+			usages = append(usages, quotaUsage)
+		}
+	}
+
+	return usages, nil
+}
+
+// The PostgreSQL flexible server quota usages aren't covered by the Azure SDK module used by this project.
+func (a *AzureQuota) getPostgresqlUsages(ctx context.Context, location *AzureLocation) ([]*QuotaUsage, error) {
+	path := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.DBforPostgreSQL/locations/%s/resourceType/flexibleServers/usages", url.PathEscape(a.session.subscriptionId), url.PathEscape(location.Name))
+
+	list := &postgresqlQuotaUsageList{}
+	if err := armGet(ctx, a.session, path, postgresqlQuotaUsagesApiVersion, list); err != nil {
+		return nil, err
+	}
+
+	usages := []*QuotaUsage{}
+	for _, usage := range list.Value {
+		if usage == nil || usage.Name == nil {
+			continue
+		}
+		usages = append(usages, &QuotaUsage{
+			Location:    location.Name,
+			Provider:    PostgresqlQuotaProvider,
+			Name:        usage.Name.Value,
+			DisplayName: usage.Name.LocalizedValue,
+			Current:     usage.CurrentValue,
+			Limit:       usage.Limit,
+			Unit:        usage.Unit,
+		})
+	}
+
+	return usages, nil
+}
+
+// AttachQuotas adds the usages of the quotas to the results of their location. The deployable locations whose
+// usages can't be read are marked as unknown, their quota isn't verified. They are marked as cancelled when the
+// verification was cancelled, the quota isn't the problem.
+func (list *VerificationResultList) AttachQuotas(ctx context.Context, usages map[string][]*QuotaUsage, errs map[string]error, quotas []QuotaName) {
+	for _, result := range list.Value {
+		if err, ok := errs[result.Location.Name]; ok && result.IsDeployable() {
+			result.Status = StatusUnknown
+			if ctx.Err() != nil {
+				result.ReasonCode = CancelledReason
+				result.Evidence = fmt.Sprintf("the verification was cancelled before the quota was verified: %s", context.Cause(ctx))
+			} else {
+				result.ReasonCode = QuotaUnverifiedReason
+				result.Evidence = fmt.Sprintf("the quota couldn't be verified: %s", err)
+			}
+		}
+
+		for _, usage := range usages[result.Location.Name] {
+			for _, quota := range quotas {
+				if usage.Provider == quota.Provider && (quota.Name == "" || strings.EqualFold(usage.Name, quota.Name)) {
+					result.Quotas = append(result.Quotas, usage)
+					break
+				}
+			}
+		}
+	}
+}
+
+// RequireQuota marks the deployable locations without enough headroom in the quota as unsupported.
+// The locations without the usage of the quota are left as is, their quota can't be verified.
+func (list *VerificationResultList) RequireQuota(quota QuotaName, required int64) {
+	for _, result := range list.Value {
+		if !result.IsDeployable() {
+			continue
+		}
+
+		for _, usage := range result.Quotas {
+			if usage.Provider != quota.Provider || !strings.EqualFold(usage.Name, quota.Name) {
+				continue
+			}
+
+			if usage.Remaining() < required {
+				result.Status = StatusUnsupported
+				result.ReasonCode = InsufficientQuotaReason
+				result.Evidence = fmt.Sprintf("%d %s required, %d of %d available", required, quota.Name, usage.Remaining(), usage.Limit)
+			}
+		}
+	}
+}
+
+// QuotaString returns the headroom of the quotas of the result, e.g. "cores: 84/100".
+func (r *VerificationResult) QuotaString() string {
+	quotas := make([]string, 0, len(r.Quotas))
+	for _, usage := range r.Quotas {
+		quotas = append(quotas, fmt.Sprintf("%s: %d/%d", usage.Name, usage.Remaining(), usage.Limit))
+	}
+	return strings.Join(quotas, ", ")
+}
@@ -0,0 +1,123 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRequireQuota(t *testing.T) {
+	eastus := &AzureLocation{Name: "eastus", DisplayName: "East US"}
+	westus := &AzureLocation{Name: "westus", DisplayName: "West US"}
+	centralus := &AzureLocation{Name: "centralus", DisplayName: "Central US"}
+	westeurope := &AzureLocation{Name: "westeurope", DisplayName: "West Europe"}
+
+	usages := map[string][]*QuotaUsage{
+		"eastus": {
+			{Location: "eastus", Provider: ComputeQuotaProvider, Name: "cores", Current: 20, Limit: 100},
+			{Location: "eastus", Provider: ComputeQuotaProvider, Name: "standardDSv5Family", Current: 0, Limit: 50},
+			{Location: "eastus", Provider: NetworkQuotaProvider, Name: "PublicIPAddresses", Current: 3, Limit: 10},
+		},
+		"westus": {
+			{Location: "westus", Provider: ComputeQuotaProvider, Name: "cores", Current: 90, Limit: 100},
+		},
+	}
+
+	list := &VerificationResultList{
+		Value: []*VerificationResult{
+			NewSupportedResult(KubernetesService, eastus, ""),
+			NewSupportedResult(KubernetesService, westus, ""),
+			NewSupportedResult(KubernetesService, centralus, ""),
+			NewSupportedResult(KubernetesService, westeurope, ""),
+		},
+	}
+
+	errs := map[string]error{
+		"westeurope": errors.New("error getting the compute quota usages: 429 Too Many Requests"),
+	}
+
+	list.AttachQuotas(context.Background(), usages, errs, ServiceQuotas(KubernetesService))
+	list.RequireQuota(QuotaName{Provider: ComputeQuotaProvider, Name: CoresQuotaName}, 16)
+
+	if got := list.Value[0].QuotaString(); got != "cores: 80/100, PublicIPAddresses: 7/10" {
+		t.Errorf("QuotaString() = %s, want cores: 80/100, PublicIPAddresses: 7/10", got)
+	}
+
+	tests := []struct {
+		name       string
+		result     *VerificationResult
+		wantStatus VerificationStatus
+		wantReason string
+	}{
+		{
+			name:       "Test enough quota",
+			result:     list.Value[0],
+			wantStatus: StatusSupported,
+		},
+		{
+			name:       "Test insufficient quota",
+			result:     list.Value[1],
+			wantStatus: StatusUnsupported,
+			wantReason: InsufficientQuotaReason,
+		},
+		{
+			name:       "Test unknown quota",
+			result:     list.Value[2],
+			wantStatus: StatusSupported,
+		},
+		{
+			name:       "Test unverified quota",
+			result:     list.Value[3],
+			wantStatus: StatusUnknown,
+			wantReason: QuotaUnverifiedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.result.Status != tt.wantStatus {
+				t.Errorf("RequireQuota() status = %s, want %s", tt.result.Status, tt.wantStatus)
+			}
+			if tt.result.ReasonCode != tt.wantReason {
+				t.Errorf("RequireQuota() reason = %s, want %s", tt.result.ReasonCode, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAttachQuotas(t *testing.T) {
+	westeurope := &AzureLocation{Name: "westeurope", DisplayName: "West Europe"}
+
+	tests := []struct {
+		name       string
+		cancelled  bool
+		wantReason string
+	}{
+		{
+			name:       "Test usages not read",
+			wantReason: QuotaUnverifiedReason,
+		},
+		{
+			name:       "Test usages not read after a cancellation",
+			cancelled:  true,
+			wantReason: CancelledReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+
+			list := &VerificationResultList{Value: []*VerificationResult{NewSupportedResult(KubernetesService, westeurope, "")}}
+			errs := map[string]error{"westeurope": errors.New("error getting the compute quota usages")}
+
+			list.AttachQuotas(ctx, map[string][]*QuotaUsage{}, errs, ServiceQuotas(KubernetesService))
+
+			if got := list.Value[0]; got.Status != StatusUnknown || got.ReasonCode != tt.wantReason {
+				t.Errorf("AttachQuotas() = %s %s, want %s %s", got.Status, got.ReasonCode, StatusUnknown, tt.wantReason)
+			}
+		})
+	}
+}
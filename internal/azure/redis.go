@@ -4,86 +4,67 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 )
 
 type AzureRedisCache struct {
-	cred           *azidentity.DefaultAzureCredential
-	ctx            context.Context
-	subscriptionId string
+	session *Session
 }
 
-func NewAzureRedisCache(cred *azidentity.DefaultAzureCredential, ctx context.Context, subscriptionId string) *AzureRedisCache {
+func NewAzureRedisCache(session *Session) *AzureRedisCache {
 	return &AzureRedisCache{
-		cred:           cred,
-		ctx:            ctx,
-		subscriptionId: subscriptionId,
+		session: session,
 	}
 }
 
-func (a *AzureRedisCache) GetRedisLocations() (*AzureLocationList, error) {
-	clientFactory, err := armresources.NewClientFactory(a.subscriptionId, a.cred, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create the arm resource client factory %w", err)
+func (a *AzureRedisCache) GetRedisLocations(ctx context.Context, locations *AzureLocationList) (*VerificationResultList, error) {
+	provider, err := a.session.offeredProvider(ctx, "Microsoft.Cache")
+	if err != nil && ctx.Err() != nil {
+		return NewCancelledResults(ctx, RedisService, locations), nil
 	}
-
-	res, err := clientFactory.NewProvidersClient().Get(a.ctx, "Microsoft.Cache", &armresources.ProvidersClientGetOptions{Expand: nil})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get the cache provider %w", err)
-	}
-
-	azureLocationLocator := NewAzureLocationLocator(a.cred, a.ctx, a.subscriptionId)
-	azureLocations, err := azureLocationLocator.GetLocations()
 	if err != nil {
 		return nil, err
 	}
 
-	// Create a map of display name to location
-	displayNameToLocation := make(map[string]*AzureLocation)
-	for _, location := range azureLocations.Value {
-		displayNameToLocation[location.DisplayName] = location
-	}
-
-	redisLocations, err := getRedisLocations(&res.Provider)
+	redisType, err := getResourceType(provider, "Redis")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get the Azure Cache for Redis locations %w", err)
 	}
 
-	locations := &AzureLocationList{
-		Value: []*AzureLocation{},
-	}
+	// Azure Cache for Redis Enterprise is a separate resource type with its own locations, nil if it isn't offered
+	enterpriseType, _ := getResourceType(provider, "redisEnterprise")
 
-	for _, location := range redisLocations {
-		if regionName, ok := displayNameToLocation[*location]; ok {
-			locations.Value = append(locations.Value, regionName)
-		}
-	}
-
-	return locations, nil
+	return evaluateRedisLocations(locations, redisType, enterpriseType), nil
 }
 
-func getRedisLocations(provider *armresources.Provider) ([]*string, error) {
-	if provider.ResourceTypes == nil {
-		return nil, fmt.Errorf("failed to get the cache provider resource types")
+// evaluateRedisLocations verifies Azure Cache for Redis is offered in the locations. A location is zone redundant
+// when the resource type has more than one availability zone in it, and supports Redis Enterprise when the
+// redisEnterprise resource type, if any, is offered in it.
+func evaluateRedisLocations(locations *AzureLocationList, redisType *armresources.ProviderResourceType, enterpriseType *armresources.ProviderResourceType) *VerificationResultList {
+	// The provider returns the location display names
+	redisDisplayNames := toSet(redisType.Locations)
+	redisZones := zonesByLocation(redisType)
+
+	enterpriseDisplayNames := make(map[string]struct{})
+	if enterpriseType != nil {
+		enterpriseDisplayNames = toSet(enterpriseType.Locations)
 	}
 
-	for _, resourceType := range provider.ResourceTypes {
-		if resourceType.ResourceType == nil {
-			continue
-		}
-
-		// We're looking for locations for Redis
-		if *resourceType.ResourceType != "Redis" {
-			continue
-		}
+	results := &VerificationResultList{
+		Value: []*VerificationResult{},
+	}
 
-		if resourceType.Locations == nil {
+	for _, location := range locations.Value {
+		if _, ok := redisDisplayNames[location.DisplayName]; !ok {
+			results.Value = append(results.Value, NewUnsupportedResult(RedisService, location, LocationNotOfferedReason, "Microsoft.Cache/Redis is not offered in this location"))
 			continue
 		}
 
-		return resourceType.Locations, nil
+		result := NewSupportedResult(RedisService, location, "Microsoft.Cache/Redis is offered in this location")
+		result.Features[ZoneRedundancyFeature] = len(redisZones[location.DisplayName]) > 1
+		_, result.Features[RedisEnterpriseFeature] = enterpriseDisplayNames[location.DisplayName]
+		results.Value = append(results.Value, result)
 	}
 
-	return nil, fmt.Errorf("no Redis locations found")
+	return results
 }
@@ -0,0 +1,73 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+func TestEvaluateRedisLocations(t *testing.T) {
+	redisType := &armresources.ProviderResourceType{
+		ResourceType: to.Ptr("Redis"),
+		Locations:    []*string{to.Ptr("East US 2"), to.Ptr("West Europe"), to.Ptr("Brazil South")},
+		ZoneMappings: []*armresources.ZoneMapping{
+			{Location: to.Ptr("East US 2"), Zones: []*string{to.Ptr("1"), to.Ptr("2"), to.Ptr("3")}},
+			{Location: to.Ptr("West Europe"), Zones: []*string{to.Ptr("1")}},
+		},
+	}
+	enterpriseType := &armresources.ProviderResourceType{
+		ResourceType: to.Ptr("redisEnterprise"),
+		Locations:    []*string{to.Ptr("East US 2")},
+	}
+
+	tests := []struct {
+		name              string
+		location          *AzureLocation
+		enterpriseType    *armresources.ProviderResourceType
+		wantStatus        VerificationStatus
+		wantZoneRedundant bool
+		wantEnterprise    bool
+	}{
+		{
+			name:              "Test location with zones",
+			location:          &AzureLocation{Name: "eastus2", DisplayName: "East US 2"},
+			enterpriseType:    enterpriseType,
+			wantStatus:        StatusSupported,
+			wantZoneRedundant: true,
+			wantEnterprise:    true,
+		},
+		{
+			name:           "Test location with a single zone",
+			location:       &AzureLocation{Name: "westeurope", DisplayName: "West Europe"},
+			enterpriseType: enterpriseType,
+			wantStatus:     StatusSupported,
+		},
+		{
+			name:       "Test location without zones nor enterprise",
+			location:   &AzureLocation{Name: "brazilsouth", DisplayName: "Brazil South"},
+			wantStatus: StatusSupported,
+		},
+		{
+			name:           "Test location not offered",
+			location:       &AzureLocation{Name: "norwayeast", DisplayName: "Norway East"},
+			enterpriseType: enterpriseType,
+			wantStatus:     StatusUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locations := &AzureLocationList{Value: []*AzureLocation{tt.location}}
+			got := evaluateRedisLocations(locations, redisType, tt.enterpriseType).Value[0]
+			if got.Status != tt.wantStatus {
+				t.Errorf("evaluateRedisLocations() status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if got.HasFeature(ZoneRedundancyFeature) != tt.wantZoneRedundant {
+				t.Errorf("evaluateRedisLocations() zone redundant = %t, want %t", got.HasFeature(ZoneRedundancyFeature), tt.wantZoneRedundant)
+			}
+			if got.HasFeature(RedisEnterpriseFeature) != tt.wantEnterprise {
+				t.Errorf("evaluateRedisLocations() enterprise = %t, want %t", got.HasFeature(RedisEnterpriseFeature), tt.wantEnterprise)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// The registration states of a resource provider in a subscription
+const (
+	RegisteredState    = "Registered"
+	RegisteringState   = "Registering"
+	NotRegisteredState = "NotRegistered"
+)
+
+// The resource provider namespaces a service is deployed with
+var serviceNamespaces = map[string][]string{
+	RedisService:             {"Microsoft.Cache"},
+	PostgresqlService:        {"Microsoft.DBforPostgreSQL"},
+	MysqlService:             {"Microsoft.DBforMySQL"},
+	SqlDatabaseService:       {"Microsoft.Sql"},
+	VirtualMachineSkuService: {"Microsoft.Compute"},
+	KubernetesService:        {"Microsoft.ContainerService", "Microsoft.Compute"},
+	WebAppService:            {"Microsoft.Web"},
+}
+
+// ServiceNamespaces returns the resource provider namespaces of a service.
+// The service of a resource type, e.g. Microsoft.App/managedEnvironments, is deployed with the namespace of the type.
+func ServiceNamespaces(service string) []string {
+	if namespaces, ok := serviceNamespaces[service]; ok {
+		return namespaces
+	}
+
+	if namespace, _, err := ParseResourceType(service); err == nil {
+		return []string{namespace}
+	}
+
+	return nil
+}
+
+// ProviderRegistration is the registration state of a resource provider in the subscription.
+type ProviderRegistration struct {
+	Namespace         string
+	RegistrationState string
+}
+
+func (r *ProviderRegistration) IsRegistered() bool {
+	return strings.EqualFold(r.RegistrationState, RegisteredState)
+}
+
+// ProviderRegistrations are the registrations keyed by the namespace.
+type ProviderRegistrations map[string]*ProviderRegistration
+
+// Unregistered returns the registrations that aren't registered, sorted by namespace.
+func (registrations ProviderRegistrations) Unregistered() []*ProviderRegistration {
+	unregistered := []*ProviderRegistration{}
+	for _, registration := range registrations {
+		if !registration.IsRegistered() {
+			unregistered = append(unregistered, registration)
+		}
+	}
+
+	slices.SortFunc(unregistered, func(a, b *ProviderRegistration) int {
+		return strings.Compare(a.Namespace, b.Namespace)
+	})
+
+	return unregistered
+}
+
+// GetProviderRegistrations returns the registration state of the resource providers in the subscription.
+func (a *AzureResourceProvider) GetProviderRegistrations(ctx context.Context, namespaces []string) (ProviderRegistrations, error) {
+	registrations := ProviderRegistrations{}
+
+	for _, namespace := range namespaces {
+		if _, ok := registrations[namespace]; ok {
+			continue
+		}
+
+		log.Printf("Getting the registration state of %s", namespace)
+		provider, err := getProvider(ctx, a.session, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		registrations[namespace] = newProviderRegistration(namespace, provider)
+	}
+
+	return registrations, nil
+}
+
+// RegisterProvider registers the resource provider in the subscription.
+// The registration completes asynchronously, the provider is usually in the Registering state when this returns.
+func (a *AzureResourceProvider) RegisterProvider(ctx context.Context, namespace string) (*ProviderRegistration, error) {
+	clientFactory, err := a.session.resourcesClientFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := clientFactory.NewProvidersClient().Register(ctx, namespace, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register the %s provider %w", namespace, err)
+	}
+
+	return newProviderRegistration(namespace, &res.Provider), nil
+}
+
+func newProviderRegistration(namespace string, provider *armresources.Provider) *ProviderRegistration {
+	registration := &ProviderRegistration{
+		Namespace:         namespace,
+		RegistrationState: NotRegisteredState,
+	}
+
+	if provider.RegistrationState != nil {
+		registration.RegistrationState = *provider.RegistrationState
+	}
+
+	return registration
+}
+
+// Namespaces returns the resource provider namespaces of the services in the list.
+func (list *VerificationResultList) Namespaces() []string {
+	namespaces := []string{}
+	for _, result := range list.Value {
+		for _, namespace := range ServiceNamespaces(result.Service) {
+			if !slices.Contains(namespaces, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	return namespaces
+}
+
+// RequireRegistration marks the deployable locations of the services whose resource providers aren't registered
+// as unsupported. A provider that is still registering degrades the locations.
+func (list *VerificationResultList) RequireRegistration(registrations ProviderRegistrations) {
+	for _, result := range list.Value {
+		if !result.IsDeployable() {
+			continue
+		}
+
+		for _, namespace := range ServiceNamespaces(result.Service) {
+			registration, ok := registrations[namespace]
+			if !ok || registration.IsRegistered() {
+				continue
+			}
+
+			if strings.EqualFold(registration.RegistrationState, RegisteringState) {
+				result.Status = StatusDegraded
+				result.ReasonCode = ProviderRegisteringReason
+				result.Evidence = fmt.Sprintf("the %s resource provider is registering in the subscription", namespace)
+				continue
+			}
+
+			result.Status = StatusUnsupported
+			result.ReasonCode = ProviderNotRegisteredReason
+			result.Evidence = fmt.Sprintf("the %s resource provider is %s in the subscription", namespace, registration.RegistrationState)
+			break
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package azure
+
+import (
+	"testing"
+)
+
+func TestRequireRegistration(t *testing.T) {
+	location := &AzureLocation{Name: "eastus", DisplayName: "East US"}
+
+	registrations := ProviderRegistrations{
+		"Microsoft.Cache":            {Namespace: "Microsoft.Cache", RegistrationState: RegisteredState},
+		"Microsoft.DBforPostgreSQL":  {Namespace: "Microsoft.DBforPostgreSQL", RegistrationState: NotRegisteredState},
+		"Microsoft.ContainerService": {Namespace: "Microsoft.ContainerService", RegistrationState: RegisteringState},
+		"Microsoft.Compute":          {Namespace: "Microsoft.Compute", RegistrationState: RegisteredState},
+		"Microsoft.App":              {Namespace: "Microsoft.App", RegistrationState: "Unregistered"},
+	}
+
+	tests := []struct {
+		name       string
+		result     *VerificationResult
+		wantStatus VerificationStatus
+		wantReason string
+	}{
+		{
+			name:       "Test registered provider",
+			result:     NewSupportedResult(RedisService, location, ""),
+			wantStatus: StatusSupported,
+		},
+		{
+			name:       "Test provider not registered",
+			result:     NewSupportedResult(PostgresqlService, location, ""),
+			wantStatus: StatusUnsupported,
+			wantReason: ProviderNotRegisteredReason,
+		},
+		{
+			name:       "Test provider registering",
+			result:     NewSupportedResult(KubernetesService, location, ""),
+			wantStatus: StatusDegraded,
+			wantReason: ProviderRegisteringReason,
+		},
+		{
+			name:       "Test provider of a resource type",
+			result:     NewSupportedResult("Microsoft.App/managedEnvironments", location, ""),
+			wantStatus: StatusUnsupported,
+			wantReason: ProviderNotRegisteredReason,
+		},
+		{
+			name:       "Test location already unsupported",
+			result:     NewUnsupportedResult(PostgresqlService, location, LocationNotOfferedReason, ""),
+			wantStatus: StatusUnsupported,
+			wantReason: LocationNotOfferedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := &VerificationResultList{Value: []*VerificationResult{tt.result}}
+			list.RequireRegistration(registrations)
+			if tt.result.Status != tt.wantStatus {
+				t.Errorf("RequireRegistration() status = %s, want %s", tt.result.Status, tt.wantStatus)
+			}
+			if tt.result.ReasonCode != tt.wantReason {
+				t.Errorf("RequireRegistration() reason = %s, want %s", tt.result.ReasonCode, tt.wantReason)
+			}
+		})
+	}
+
+	if got := registrations.Unregistered(); len(got) != 3 || got[0].Namespace != "Microsoft.App" {
+		t.Errorf("Unregistered() = %v, want Microsoft.App, Microsoft.ContainerService and Microsoft.DBforPostgreSQL", got)
+	}
+}
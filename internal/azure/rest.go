@@ -0,0 +1,87 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// The module name and version reported in the user agent of the REST requests
+const (
+	moduleName    = "azure-resource-verifier"
+	moduleVersion = "v0.1.0"
+)
+
+// armGet sends a GET request to the Azure Resource Manager API and decodes the json response into result.
+// It is used for the APIs that are not covered by the Azure SDK modules used by this project.
+// A response with an unexpected status code is returned as an *azcore.ResponseError.
+func armGet(ctx context.Context, session *Session, path string, apiVersion string, result any) error {
+	return armGetWithQuery(ctx, session, path, url.Values{"api-version": []string{apiVersion}}, result)
+}
+
+// armGetWithQuery is armGet with additional query parameters, e.g. $filter. The query must contain the api-version.
+// The path can also be the absolute url of the next page of a list.
+func armGetWithQuery(ctx context.Context, session *Session, path string, query url.Values, result any) error {
+	client, err := session.armClient()
+	if err != nil {
+		return err
+	}
+
+	endpoint := path
+	if !strings.HasPrefix(path, "https://") {
+		endpoint = runtime.JoinPaths(client.Endpoint(), path)
+	}
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return err
+	}
+
+	if query != nil {
+		rawQuery := req.Raw().URL.Query()
+		for key, values := range query {
+			rawQuery[key] = values
+		}
+		req.Raw().URL.RawQuery = rawQuery.Encode()
+	}
+	req.Raw().Header["Accept"] = []string{"application/json"}
+
+	resp, err := client.Pipeline().Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return runtime.NewResponseError(resp)
+	}
+
+	return runtime.UnmarshalAsJSON(resp, result)
+}
+
+// armPage is a page of an Azure Resource Manager list API
+type armPage[T any] struct {
+	Value    []T    `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// armList returns the items of all the pages of an Azure Resource Manager list API.
+func armList[T any](ctx context.Context, session *Session, path string, query url.Values) ([]T, error) {
+	items := []T{}
+
+	for path != "" {
+		page := &armPage[T]{}
+		if err := armGetWithQuery(ctx, session, path, query, page); err != nil {
+			return nil, err
+		}
+		items = append(items, page.Value...)
+
+		// The next link contains the query of the request
+		path = page.NextLink
+		query = nil
+	}
+
+	return items, nil
+}
@@ -0,0 +1,274 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/mysql/armmysqlflexibleservers"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/postgresql/armpostgresqlflexibleservers"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
+	"github.com/nickdala/azure-resource-verifier/internal/cache"
+)
+
+// SessionOptions configures the clients of a session. The zero value calls the Azure public cloud with the request
+// timeout of the Azure SDK, the default concurrency and no cache.
+type SessionOptions struct {
+	// The cloud the clients of the Azure Resource Manager API are created for
+	Cloud cloud.Configuration
+	// The maximum duration of a single request to the Azure Resource Manager API, retries excluded.
+	// A request that times out is retried. Zero keeps the default of the Azure SDK.
+	RequestTimeout time.Duration
+	// The limiter of the requests of the per-location verifications in flight, shared by the sessions of a command
+	Limiter *RequestLimiter
+	// The cache of the responses of the Azure APIs. Nil disables the cache
+	Cache *cache.Cache
+}
+
+// Session owns the credential of a subscription and the clients of the Azure APIs, created once and shared by
+// the verifications of a command. The lookups shared by the verifications, e.g. the locations of the subscription
+// and the resource providers, are memoized. The context is given per call.
+type Session struct {
+	cred           azcore.TokenCredential
+	subscriptionId string
+
+	cloud          cloud.Configuration
+	requestTimeout time.Duration
+	limiter        *RequestLimiter
+	cache          *cache.Cache
+
+	// The clients and client factories, keyed by the API
+	clientsMu sync.Mutex
+	clients   map[string]any
+
+	// The locations of the subscription
+	locationsMu sync.Mutex
+	locations   *AzureLocationList
+
+	// The resource providers, keyed by the lower case namespace
+	providersMu sync.Mutex
+	providers   map[string]*armresources.Provider
+
+	// The requests of the subscription are slowed down when it is close to being throttled
+	throttle *throttlePolicy
+}
+
+// NewSession returns the session of the subscription. The subscription id is empty for the APIs of the tenant,
+// e.g. the list of the subscriptions the credential can access.
+func NewSession(cred azcore.TokenCredential, subscriptionId string, options SessionOptions) *Session {
+	if options.Cloud.Services == nil {
+		options.Cloud = cloud.AzurePublic
+	}
+	if options.Limiter == nil {
+		options.Limiter = NewRequestLimiter(DefaultConcurrency)
+	}
+
+	return &Session{
+		cred:           cred,
+		subscriptionId: subscriptionId,
+		cloud:          options.Cloud,
+		requestTimeout: options.RequestTimeout,
+		limiter:        options.Limiter,
+		cache:          options.Cache,
+		clients:        map[string]any{},
+		providers:      map[string]*armresources.Provider{},
+		throttle:       &throttlePolicy{},
+	}
+}
+
+func (s *Session) Credential() azcore.TokenCredential {
+	return s.cred
+}
+
+func (s *Session) SubscriptionId() string {
+	return s.subscriptionId
+}
+
+// Locations returns the locations of the subscription. They are read once per session.
+func (s *Session) Locations(ctx context.Context) (*AzureLocationList, error) {
+	s.locationsMu.Lock()
+	defer s.locationsMu.Unlock()
+
+	if s.locations != nil {
+		return s.locations, nil
+	}
+
+	locations, err := NewAzureLocationLocator(s).GetLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.locations = locations
+	return locations, nil
+}
+
+// offeredProvider returns the resource provider with the resource types it offers. The provider is read once per session.
+func (s *Session) offeredProvider(ctx context.Context, namespace string) (*armresources.Provider, error) {
+	s.providersMu.Lock()
+	defer s.providersMu.Unlock()
+
+	key := strings.ToLower(namespace)
+	if provider, ok := s.providers[key]; ok {
+		return provider, nil
+	}
+
+	provider, err := getOfferedProvider(ctx, s, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	s.providers[key] = provider
+	return provider, nil
+}
+
+// Cloud returns the cloud the clients of the session are created for.
+func (s *Session) Cloud() cloud.Configuration {
+	return s.cloud
+}
+
+// clientOptions returns the options of the clients of the session, with the throttling of the subscription.
+func (s *Session) clientOptions() *arm.ClientOptions {
+	options := armClientOptions(s.cloud, s.requestTimeout)
+	options.PerCallPolicies = append(options.PerCallPolicies, s.throttle)
+	return options
+}
+
+// client returns the client of the API, created by the create function the first time.
+func client[T any](s *Session, api string, create func() (T, error)) (T, error) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
+	if c, ok := s.clients[api]; ok {
+		return c.(T), nil
+	}
+
+	c, err := create()
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("failed to create the %s client: %w", api, err)
+	}
+
+	s.clients[api] = c
+	return c, nil
+}
+
+// armClient returns the client of the REST requests of the APIs that are not covered by the Azure SDK modules.
+func (s *Session) armClient() (*arm.Client, error) {
+	return client(s, "arm", func() (*arm.Client, error) {
+		return arm.NewClient(moduleName, moduleVersion, s.cred, s.clientOptions())
+	})
+}
+
+func (s *Session) subscriptionsClientFactory() (*armsubscriptions.ClientFactory, error) {
+	return client(s, "subscriptions", func() (*armsubscriptions.ClientFactory, error) {
+		return armsubscriptions.NewClientFactory(s.cred, s.clientOptions())
+	})
+}
+
+func (s *Session) resourcesClientFactory() (*armresources.ClientFactory, error) {
+	return client(s, "resources", func() (*armresources.ClientFactory, error) {
+		return armresources.NewClientFactory(s.subscriptionId, s.cred, s.clientOptions())
+	})
+}
+
+func (s *Session) computeClientFactory() (*armcompute.ClientFactory, error) {
+	return client(s, "compute", func() (*armcompute.ClientFactory, error) {
+		return armcompute.NewClientFactory(s.subscriptionId, s.cred, s.clientOptions())
+	})
+}
+
+func (s *Session) networkClientFactory() (*armnetwork.ClientFactory, error) {
+	return client(s, "network", func() (*armnetwork.ClientFactory, error) {
+		return armnetwork.NewClientFactory(s.subscriptionId, s.cred, s.clientOptions())
+	})
+}
+
+func (s *Session) appServiceClientFactory() (*armappservice.ClientFactory, error) {
+	return client(s, "app service", func() (*armappservice.ClientFactory, error) {
+		return armappservice.NewClientFactory(s.subscriptionId, s.cred, s.clientOptions())
+	})
+}
+
+func (s *Session) containerServiceClientFactory() (*armcontainerservice.ClientFactory, error) {
+	return client(s, "container service", func() (*armcontainerservice.ClientFactory, error) {
+		return armcontainerservice.NewClientFactory(s.subscriptionId, s.cred, s.clientOptions())
+	})
+}
+
+func (s *Session) resourceSkusClient() (*armcompute.ResourceSKUsClient, error) {
+	clientFactory, err := s.computeClientFactory()
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.NewResourceSKUsClient(), nil
+}
+
+func (s *Session) computeUsageClient() (*armcompute.UsageClient, error) {
+	clientFactory, err := s.computeClientFactory()
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.NewUsageClient(), nil
+}
+
+func (s *Session) networkUsagesClient() (*armnetwork.UsagesClient, error) {
+	clientFactory, err := s.networkClientFactory()
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.NewUsagesClient(), nil
+}
+
+func (s *Session) managedClustersClient() (*armcontainerservice.ManagedClustersClient, error) {
+	clientFactory, err := s.containerServiceClientFactory()
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.NewManagedClustersClient(), nil
+}
+
+func (s *Session) sqlCapabilitiesClient() (*armsql.CapabilitiesClient, error) {
+	clientFactory, err := s.sqlClientFactory()
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.NewCapabilitiesClient(), nil
+}
+
+func (s *Session) mysqlLocationBasedCapabilitiesClient() (*armmysqlflexibleservers.LocationBasedCapabilitiesClient, error) {
+	clientFactory, err := s.mysqlClientFactory()
+	if err != nil {
+		return nil, err
+	}
+	return clientFactory.NewLocationBasedCapabilitiesClient(), nil
+}
+
+func (s *Session) sqlClientFactory() (*armsql.ClientFactory, error) {
+	return client(s, "sql", func() (*armsql.ClientFactory, error) {
+		return armsql.NewClientFactory(s.subscriptionId, s.cred, s.clientOptions())
+	})
+}
+
+func (s *Session) mysqlClientFactory() (*armmysqlflexibleservers.ClientFactory, error) {
+	return client(s, "mysql flexible server", func() (*armmysqlflexibleservers.ClientFactory, error) {
+		return armmysqlflexibleservers.NewClientFactory(s.subscriptionId, s.cred, s.clientOptions())
+	})
+}
+
+// The PostgreSQL module has no client factory
+func (s *Session) postgresqlCapabilitiesClient() (*armpostgresqlflexibleservers.LocationBasedCapabilitiesClient, error) {
+	return client(s, "postgresql flexible server", func() (*armpostgresqlflexibleservers.LocationBasedCapabilitiesClient, error) {
+		return armpostgresqlflexibleservers.NewLocationBasedCapabilitiesClient(s.subscriptionId, s.cred, s.clientOptions())
+	})
+}
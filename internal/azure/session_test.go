@@ -0,0 +1,61 @@
+package azure
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSessionClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		errs        []error
+		wantCreates int
+		wantErrs    []bool
+	}{
+		{
+			name:        "Test client created once",
+			errs:        []error{nil, nil, nil},
+			wantCreates: 1,
+			wantErrs:    []bool{false, false, false},
+		},
+		{
+			name:        "Test failed client created again",
+			errs:        []error{errors.New("no credential"), nil, nil},
+			wantCreates: 2,
+			wantErrs:    []bool{true, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := NewSession(nil, "00000000-0000-0000-0000-000000000000", SessionOptions{})
+
+			creates := 0
+			var first *int
+			for i, wantErr := range tt.wantErrs {
+				c, err := client(session, "test", func() (*int, error) {
+					creates++
+					if tt.errs[creates-1] != nil {
+						return nil, tt.errs[creates-1]
+					}
+					return new(int), nil
+				})
+				if (err != nil) != wantErr {
+					t.Fatalf("client() call %d error = %v, wantErr %v", i, err, wantErr)
+				}
+				if err != nil {
+					continue
+				}
+				if first == nil {
+					first = c
+				} else if c != first {
+					t.Errorf("client() call %d returned a new client", i)
+				}
+			}
+
+			if creates != tt.wantCreates {
+				t.Errorf("client() created %d clients, want %d", creates, tt.wantCreates)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
+)
+
+type AzureSqlDatabase struct {
+	session *Session
+}
+
+func NewAzureSqlDatabase(session *Session) *AzureSqlDatabase {
+	return &AzureSqlDatabase{
+		session: session,
+	}
+}
+
+// SqlDatabaseEditions lists the editions of Azure SQL Database.
+var SqlDatabaseEditions = []string{"Basic", "Standard", "Premium", "GeneralPurpose", "BusinessCritical", "Hyperscale", "DataWarehouse", "Free"}
+
+// GetSqlDatabaseLocations verifies the locations support Azure SQL Database.
+// If edition or serviceObjective are not empty, the locations must support them as well.
+// The service objective can either be the name of the objective (e.g. GP_S_Gen5_2) or the SKU name (e.g. GP_S_Gen5).
+func (a *AzureSqlDatabase) GetSqlDatabaseLocations(ctx context.Context, locations *AzureLocationList, edition string, serviceObjective string) (*VerificationResultList, error) {
+	client, err := a.session.sqlCapabilitiesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// The following is used to store results from our go routine.
+	// We will merge the results after all go routines are done.
+	results := make([]*VerificationResult, len(locations.Value))
+
+	a.session.limiter.forEachLocation(locations, func(idx int, azureLocation *AzureLocation) {
+		log.Printf("Getting SQL Database capabilities for location %s", azureLocation.DisplayName)
+		capabilities, err := cached(a.session, "sql/capabilities", azureLocation.Name, capabilitiesCacheTTL, func() (*armsql.LocationCapabilities, error) {
+			res, err := client.ListByLocation(ctx, azureLocation.Name, &armsql.CapabilitiesClientListByLocationOptions{
+				Include: to.Ptr(armsql.CapabilityGroupSupportedEditions),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &res.LocationCapabilities, nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				results[idx] = NewCancelledResult(ctx, SqlDatabaseService, azureLocation)
+			} else if azureErr, ok := err.(*azcore.ResponseError); ok && !isTransientError(azureErr) {
+				results[idx] = NewUnsupportedResult(SqlDatabaseService, azureLocation, azureErr.ErrorCode, azureErr.Error())
+			} else if ok {
+				// The request was throttled or failed with a transient error, even after it was retried
+				results[idx] = NewUnknownResult(SqlDatabaseService, azureLocation, azureErr.ErrorCode, azureErr.Error())
+			} else {
+				results[idx] = NewUnknownResult(SqlDatabaseService, azureLocation, RequestFailedReason, err.Error())
+			}
+			return
+		}
+
+		results[idx] = evaluateSqlCapabilities(azureLocation, capabilities, edition, serviceObjective)
+	})
+
+	return &VerificationResultList{Value: removeNilItems(results)}, nil
+}
+
+// evaluateSqlCapabilities verifies the capabilities of a location support the edition and service objective.
+func evaluateSqlCapabilities(location *AzureLocation, capabilities *armsql.LocationCapabilities, edition string, serviceObjective string) *VerificationResult {
+	if status := sqlCapabilityStatus(capabilities.Status); status == StatusUnsupported {
+		return NewUnsupportedResult(SqlDatabaseService, location, CapabilityDisabledReason, valueOrDefault(capabilities.Reason, "SQL Database is disabled in this location"))
+	}
+
+	var editions []*armsql.EditionCapability
+	for _, serverVersion := range capabilities.SupportedServerVersions {
+		for _, e := range serverVersion.SupportedEditions {
+			if e.Name != nil && (edition == "" || strings.EqualFold(*e.Name, edition)) {
+				editions = append(editions, e)
+			}
+		}
+	}
+
+	if len(editions) == 0 {
+		if edition == "" {
+			return NewUnsupportedResult(SqlDatabaseService, location, NoCapabilitiesReason, "no SQL Database editions in this location")
+		}
+		return NewUnsupportedResult(SqlDatabaseService, location, EditionNotSupportedReason, fmt.Sprintf("edition %s is not offered in this location", edition))
+	}
+
+	// The status and zone redundancy are taken from the service objectives if one is requested,
+	// otherwise from the editions.
+	var statuses []*armsql.CapabilityStatus
+	var reasons []*string
+	zoneRedundant := false
+	serverless := false
+
+	for _, e := range editions {
+		if serviceObjective == "" {
+			statuses = append(statuses, e.Status)
+			reasons = append(reasons, e.Reason)
+			zoneRedundant = zoneRedundant || (e.ZoneRedundant != nil && *e.ZoneRedundant)
+		}
+
+		for _, objective := range e.SupportedServiceLevelObjectives {
+			if serviceObjective != "" && !sqlServiceObjectiveMatches(objective, serviceObjective) {
+				continue
+			}
+
+			if serviceObjective != "" {
+				statuses = append(statuses, objective.Status)
+				reasons = append(reasons, objective.Reason)
+				zoneRedundant = zoneRedundant || (objective.ZoneRedundant != nil && *objective.ZoneRedundant)
+			}
+
+			if objective.ComputeModel != nil && strings.EqualFold(*objective.ComputeModel, "Serverless") {
+				serverless = true
+			}
+		}
+	}
+
+	if len(statuses) == 0 {
+		return NewUnsupportedResult(SqlDatabaseService, location, ServiceObjectiveNotSupportedReason, fmt.Sprintf("service objective %s is not offered in this location", serviceObjective))
+	}
+
+	// The best status of the matching capabilities wins
+	supported, restricted := false, false
+	restrictedReason, disabledReason := "", ""
+	for i, status := range statuses {
+		switch sqlCapabilityStatus(status) {
+		case StatusSupported:
+			supported = true
+		case StatusDegraded:
+			restricted = true
+			if restrictedReason == "" {
+				restrictedReason = valueOrDefault(reasons[i], "")
+			}
+		default:
+			if disabledReason == "" {
+				disabledReason = valueOrDefault(reasons[i], "")
+			}
+		}
+	}
+
+	var result *VerificationResult
+	switch {
+	case supported:
+		result = NewSupportedResult(SqlDatabaseService, location, "capability is available in this location")
+	case restricted:
+		result = NewSupportedResult(SqlDatabaseService, location, "")
+		result.Status = StatusDegraded
+		result.ReasonCode = CapabilityRestrictedReason
+		result.Evidence = valueOrDefault(&restrictedReason, "capability is visible but restricted for the subscription")
+	default:
+		return NewUnsupportedResult(SqlDatabaseService, location, CapabilityDisabledReason, valueOrDefault(&disabledReason, "capability is disabled in this location"))
+	}
+
+	result.Features[ZoneRedundancyFeature] = zoneRedundant
+	result.Features[ServerlessFeature] = serverless
+
+	return result
+}
+
+func sqlServiceObjectiveMatches(objective *armsql.ServiceObjectiveCapability, serviceObjective string) bool {
+	if objective.Name != nil && strings.EqualFold(*objective.Name, serviceObjective) {
+		return true
+	}
+	return objective.SKU != nil && objective.SKU.Name != nil && strings.EqualFold(*objective.SKU.Name, serviceObjective)
+}
+
+// sqlCapabilityStatus maps the status of a SQL capability to a verification status.
+// Visible capabilities are shown but restricted, e.g. the subscription needs to request access.
+func sqlCapabilityStatus(status *armsql.CapabilityStatus) VerificationStatus {
+	if status == nil {
+		return StatusUnknown
+	}
+
+	switch *status {
+	case armsql.CapabilityStatusAvailable, armsql.CapabilityStatusDefault:
+		return StatusSupported
+	case armsql.CapabilityStatusVisible:
+		return StatusDegraded
+	default:
+		return StatusUnsupported
+	}
+}
+
+// valueOrDefault returns the value of the pointer or the default if the pointer is nil or empty.
+func valueOrDefault(value *string, defaultValue string) string {
+	if value == nil || *value == "" {
+		return defaultValue
+	}
+	return *value
+}
@@ -0,0 +1,96 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
+)
+
+func TestEvaluateSqlCapabilities(t *testing.T) {
+	location := &AzureLocation{Name: "eastus", DisplayName: "East US"}
+
+	capabilities := &armsql.LocationCapabilities{
+		Status: to.Ptr(armsql.CapabilityStatusAvailable),
+		SupportedServerVersions: []*armsql.ServerVersionCapability{
+			{
+				Name: to.Ptr("12.0"),
+				SupportedEditions: []*armsql.EditionCapability{
+					{
+						Name:          to.Ptr("GeneralPurpose"),
+						Status:        to.Ptr(armsql.CapabilityStatusDefault),
+						ZoneRedundant: to.Ptr(true),
+						SupportedServiceLevelObjectives: []*armsql.ServiceObjectiveCapability{
+							{
+								Name:          to.Ptr("GP_S_Gen5_2"),
+								Status:        to.Ptr(armsql.CapabilityStatusAvailable),
+								ComputeModel:  to.Ptr("Serverless"),
+								ZoneRedundant: to.Ptr(true),
+								SKU:           &armsql.SKU{Name: to.Ptr("GP_S_Gen5")},
+							},
+						},
+					},
+					{
+						Name:   to.Ptr("Hyperscale"),
+						Status: to.Ptr(armsql.CapabilityStatusVisible),
+						Reason: to.Ptr("Subscription does not have access to Hyperscale"),
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		edition          string
+		serviceObjective string
+		wantStatus       VerificationStatus
+		wantReason       string
+		wantServerless   bool
+	}{
+		{
+			name:           "Test any edition",
+			wantStatus:     StatusSupported,
+			wantServerless: true,
+		},
+		{
+			name:             "Test serverless SKU",
+			edition:          "GeneralPurpose",
+			serviceObjective: "GP_S_Gen5",
+			wantStatus:       StatusSupported,
+			wantServerless:   true,
+		},
+		{
+			name:       "Test restricted edition",
+			edition:    "hyperscale",
+			wantStatus: StatusDegraded,
+			wantReason: CapabilityRestrictedReason,
+		},
+		{
+			name:       "Test edition not offered",
+			edition:    "BusinessCritical",
+			wantStatus: StatusUnsupported,
+			wantReason: EditionNotSupportedReason,
+		},
+		{
+			name:             "Test service objective not offered",
+			serviceObjective: "BC_Gen5_2",
+			wantStatus:       StatusUnsupported,
+			wantReason:       ServiceObjectiveNotSupportedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateSqlCapabilities(location, capabilities, tt.edition, tt.serviceObjective)
+			if got.Status != tt.wantStatus {
+				t.Errorf("evaluateSqlCapabilities() status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if got.ReasonCode != tt.wantReason {
+				t.Errorf("evaluateSqlCapabilities() reason = %s, want %s", got.ReasonCode, tt.wantReason)
+			}
+			if got.HasFeature(ServerlessFeature) != tt.wantServerless {
+				t.Errorf("evaluateSqlCapabilities() serverless = %v, want %v", got.HasFeature(ServerlessFeature), tt.wantServerless)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const managementGroupsApiVersion = "2020-05-01"
+
+// The type of the subscriptions in the descendants of a management group
+const managementGroupSubscriptionType = "Microsoft.Management/managementGroups/subscriptions"
+
+// The state of the subscriptions that can be verified
+const enabledSubscriptionState = "Enabled"
+
+// The profile of the Azure CLI, with the subscriptions of az login and the default subscription of az account set
+const (
+	cliConfigDirVariable = "AZURE_CONFIG_DIR"
+	cliProfileFileName   = "azureProfile.json"
+)
+
+type AzureSubscription struct {
+	Id          string `json:"id" yaml:"id"`
+	DisplayName string `json:"displayName" yaml:"displayName"`
+	State       string `json:"state,omitempty" yaml:"state,omitempty"`
+	TenantId    string `json:"tenantId,omitempty" yaml:"tenantId,omitempty"`
+}
+
+// IsEnabled returns true if the subscription can be verified. The subscriptions of a management group have no state.
+func (s *AzureSubscription) IsEnabled() bool {
+	return s.State == "" || s.State == enabledSubscriptionState
+}
+
+// Name returns the display name of the subscription, or its id if it has none.
+func (s *AzureSubscription) Name() string {
+	if s.DisplayName != "" {
+		return s.DisplayName
+	}
+	return s.Id
+}
+
+type AzureSubscriptionLocator struct {
+	session *Session
+}
+
+func NewAzureSubscriptionLocator(session *Session) *AzureSubscriptionLocator {
+	return &AzureSubscriptionLocator{
+		session: session,
+	}
+}
+
+// GetSubscriptions returns the subscriptions the credential can access, sorted by display name.
+func (a *AzureSubscriptionLocator) GetSubscriptions(ctx context.Context) ([]*AzureSubscription, error) {
+	clientFactory, err := a.session.subscriptionsClientFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := []*AzureSubscription{}
+
+	pager := clientFactory.NewClient().NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the subscriptions %w", err)
+		}
+
+		for _, subscription := range page.Value {
+			if subscription.SubscriptionID == nil {
+				continue
+			}
+
+			azureSubscription := &AzureSubscription{Id: *subscription.SubscriptionID}
+			if subscription.DisplayName != nil {
+				azureSubscription.DisplayName = *subscription.DisplayName
+			}
+			if subscription.State != nil {
+				azureSubscription.State = string(*subscription.State)
+			}
+			if subscription.TenantID != nil {
+				azureSubscription.TenantId = *subscription.TenantID
+			}
+			subscriptions = append(subscriptions, azureSubscription)
+		}
+	}
+
+	sortSubscriptions(subscriptions)
+	return subscriptions, nil
+}
+
+// GetSubscription returns the subscription, if the credential can access it.
+func (a *AzureSubscriptionLocator) GetSubscription(ctx context.Context, subscriptionId string) (*AzureSubscription, error) {
+	clientFactory, err := a.session.subscriptionsClientFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := clientFactory.NewClient().Get(ctx, subscriptionId, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the subscription %s %w", subscriptionId, err)
+	}
+
+	subscription := &AzureSubscription{Id: subscriptionId}
+	if res.DisplayName != nil {
+		subscription.DisplayName = *res.DisplayName
+	}
+	if res.State != nil {
+		subscription.State = string(*res.State)
+	}
+	if res.TenantID != nil {
+		subscription.TenantId = *res.TenantID
+	}
+	return subscription, nil
+}
+
+// The descendant of a management group, i.e. a child management group or a subscription
+type managementGroupDescendant struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Properties struct {
+		DisplayName string `json:"displayName"`
+	} `json:"properties"`
+}
+
+// GetManagementGroupSubscriptions returns the subscriptions of the management group and of its child management groups,
+// sorted by display name.
+func (a *AzureSubscriptionLocator) GetManagementGroupSubscriptions(ctx context.Context, managementGroup string) ([]*AzureSubscription, error) {
+	path := fmt.Sprintf("/providers/Microsoft.Management/managementGroups/%s/descendants", url.PathEscape(managementGroup))
+	query := url.Values{"api-version": []string{managementGroupsApiVersion}}
+
+	descendants, err := armList[*managementGroupDescendant](ctx, a.session, path, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the subscriptions of the management group %s %w", managementGroup, err)
+	}
+
+	return descendantSubscriptions(descendants), nil
+}
+
+// descendantSubscriptions returns the subscriptions of the descendants of a management group.
+func descendantSubscriptions(descendants []*managementGroupDescendant) []*AzureSubscription {
+	subscriptions := []*AzureSubscription{}
+	for _, descendant := range descendants {
+		if strings.EqualFold(descendant.Type, managementGroupSubscriptionType) {
+			subscriptions = append(subscriptions, &AzureSubscription{Id: descendant.Name, DisplayName: descendant.Properties.DisplayName})
+		}
+	}
+
+	sortSubscriptions(subscriptions)
+	return subscriptions
+}
+
+func sortSubscriptions(subscriptions []*AzureSubscription) {
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return strings.ToLower(subscriptions[i].Name()) < strings.ToLower(subscriptions[j].Name())
+	})
+}
+
+// The parts of the profile of the Azure CLI used to find the default subscription
+type cliProfile struct {
+	Subscriptions []struct {
+		Id        string `json:"id"`
+		Name      string `json:"name"`
+		State     string `json:"state"`
+		TenantId  string `json:"tenantId"`
+		IsDefault bool   `json:"isDefault"`
+	} `json:"subscriptions"`
+}
+
+// GetCliDefaultSubscription returns the default subscription of the Azure CLI, i.e. the subscription of az account set,
+// from the azureProfile.json of the Azure CLI configuration directory.
+func GetCliDefaultSubscription() (*AzureSubscription, error) {
+	dir := os.Getenv(cliConfigDirVariable)
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the Azure CLI profile: %w", err)
+		}
+		dir = filepath.Join(home, ".azure")
+	}
+
+	path := filepath.Join(dir, cliProfileFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Azure CLI profile %s: %w", path, err)
+	}
+
+	return parseCliDefaultSubscription(data)
+}
+
+func parseCliDefaultSubscription(data []byte) (*AzureSubscription, error) {
+	// The Azure CLI writes the profile with a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	profile := &cliProfile{}
+	if err := json.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("failed to parse the Azure CLI profile: %w", err)
+	}
+
+	for _, subscription := range profile.Subscriptions {
+		if subscription.IsDefault {
+			return &AzureSubscription{
+				Id:          subscription.Id,
+				DisplayName: subscription.Name,
+				State:       subscription.State,
+				TenantId:    subscription.TenantId,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the Azure CLI profile has no default subscription, run az login or az account set")
+}
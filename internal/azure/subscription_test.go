@@ -0,0 +1,77 @@
+package azure
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDescendantSubscriptions(t *testing.T) {
+	data := `[
+  {"id": "/providers/Microsoft.Management/managementGroups/corp", "type": "Microsoft.Management/managementGroups", "name": "corp", "properties": {"displayName": "Corp"}},
+  {"id": "/subscriptions/2222", "type": "Microsoft.Management/managementGroups/subscriptions", "name": "2222", "properties": {"displayName": "lz-prod"}},
+  {"id": "/subscriptions/1111", "type": "Microsoft.Management/managementGroups/subscriptions", "name": "1111", "properties": {"displayName": "LZ-Dev"}},
+  {"id": "/subscriptions/3333", "type": "Microsoft.Management/managementGroups/subscriptions", "name": "3333", "properties": {}}
+]`
+
+	descendants := []*managementGroupDescendant{}
+	if err := json.Unmarshal([]byte(data), &descendants); err != nil {
+		t.Fatalf("failed to decode the descendants: %v", err)
+	}
+
+	got := descendantSubscriptions(descendants)
+
+	want := []string{"3333", "LZ-Dev", "lz-prod"}
+	if len(got) != len(want) {
+		t.Fatalf("descendantSubscriptions() got %d subscriptions, want %d", len(got), len(want))
+	}
+	for i, subscription := range got {
+		if subscription.Name() != want[i] {
+			t.Errorf("descendantSubscriptions()[%d] = %s, want %s", i, subscription.Name(), want[i])
+		}
+	}
+}
+
+func TestParseCliDefaultSubscription(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Test default subscription",
+			data: "\xef\xbb\xbf" + `{"installationId": "id", "subscriptions": [
+  {"id": "1111", "name": "dev", "state": "Enabled", "tenantId": "tenant", "isDefault": false},
+  {"id": "2222", "name": "prod", "state": "Enabled", "tenantId": "tenant", "isDefault": true}
+]}`,
+			want: "2222",
+		},
+		{
+			name:    "Test no default subscription",
+			data:    `{"subscriptions": [{"id": "1111", "name": "dev", "isDefault": false}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test no subscriptions",
+			data:    `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid profile",
+			data:    `{"subscriptions": `,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCliDefaultSubscription([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCliDefaultSubscription() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Id != tt.want {
+				t.Errorf("parseCliDefaultSubscription() = %s, want %s", got.Id, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package azure
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// The header of the read requests the subscription can still send before Azure Resource Manager throttles it
+const remainingReadsHeader = "x-ms-ratelimit-remaining-subscription-reads"
+
+// The requests are slowed down below this number of remaining read requests, up to the maximum delay
+// when no read request remains
+const (
+	throttleThreshold = 100
+	maxThrottleDelay  = 2 * time.Second
+)
+
+// throttlePolicy slows the requests of a subscription down when Azure Resource Manager reports that few read requests
+// remain, so that the verifications of many locations don't get throttled. The 429 responses are retried by the
+// retry policy of the clients, after the delay of their Retry-After header.
+type throttlePolicy struct {
+	mu    sync.Mutex
+	delay time.Duration
+}
+
+func (p *throttlePolicy) Do(req *policy.Request) (*http.Response, error) {
+	if delay := p.currentDelay(); delay > 0 {
+		log.Printf("Delaying the request to %s by %s, few read requests remain in the subscription", req.Raw().URL.Path, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Raw().Context().Done():
+			timer.Stop()
+			return nil, req.Raw().Context().Err()
+		}
+	}
+
+	resp, err := req.Next()
+	if resp != nil {
+		if remaining, parseErr := strconv.Atoi(resp.Header.Get(remainingReadsHeader)); parseErr == nil {
+			p.setDelay(throttleDelay(remaining))
+		}
+	}
+	return resp, err
+}
+
+func (p *throttlePolicy) currentDelay() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.delay
+}
+
+func (p *throttlePolicy) setDelay(delay time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.delay = delay
+}
+
+// throttleDelay returns the delay of the requests for the remaining read requests of the subscription.
+// The delay grows linearly from none at the threshold to the maximum delay when no read request remains.
+func throttleDelay(remaining int) time.Duration {
+	if remaining >= throttleThreshold {
+		return 0
+	}
+	if remaining <= 0 {
+		return maxThrottleDelay
+	}
+	return maxThrottleDelay * time.Duration(throttleThreshold-remaining) / throttleThreshold
+}
@@ -0,0 +1,81 @@
+package azure
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+func TestThrottleDelay(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining int
+		want      time.Duration
+	}{
+		{
+			name:      "Test many remaining reads",
+			remaining: 11999,
+			want:      0,
+		},
+		{
+			name:      "Test threshold",
+			remaining: throttleThreshold,
+			want:      0,
+		},
+		{
+			name:      "Test half of the threshold",
+			remaining: throttleThreshold / 2,
+			want:      maxThrottleDelay / 2,
+		},
+		{
+			name:      "Test no remaining reads",
+			remaining: 0,
+			want:      maxThrottleDelay,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := throttleDelay(tt.remaining); got != tt.want {
+				t.Errorf("throttleDelay() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		want       bool
+	}{
+		{
+			name:       "Test throttled",
+			statusCode: http.StatusTooManyRequests,
+			want:       true,
+		},
+		{
+			name:       "Test service unavailable",
+			statusCode: http.StatusServiceUnavailable,
+			want:       true,
+		},
+		{
+			name:       "Test forbidden",
+			statusCode: http.StatusForbidden,
+			want:       false,
+		},
+		{
+			name:       "Test location not offered",
+			statusCode: http.StatusBadRequest,
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientError(&azcore.ResponseError{StatusCode: tt.statusCode}); got != tt.want {
+				t.Errorf("isTransientError() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// The services that can be verified
+const (
+	RedisService             = "redis"
+	PostgresqlService        = "postgresql"
+	MysqlService             = "mysql"
+	SqlDatabaseService       = "sql"
+	VirtualMachineSkuService = "vm-sku"
+	KubernetesService        = "aks"
+	ResourceTypeService      = "provider-type"
+	WebAppService            = "web-app"
+)
+
+// The features reported by the services in a location
+const (
+	HighAvailabilityFeature = "ha"
+	ZoneRedundancyFeature   = "zones"
+	SameZoneHaFeature       = "same-zone-ha"
+	RedisEnterpriseFeature  = "enterprise"
+	ServerlessFeature       = "serverless"
+)
+
+// VersionFeature returns the feature reported when a service supports the given version, e.g. "version-16".
+func VersionFeature(version string) string {
+	return "version-" + version
+}
+
+// The reason codes used when a service can't be deployed to a location
+const (
+	LocationNotOfferedReason           = "LocationNotOffered"
+	NoCapabilitiesReason               = "NoCapabilities"
+	RequestFailedReason                = "RequestFailed"
+	HaNotSupportedReason               = "HighAvailabilityNotSupported"
+	ZonesNotSupportedReason            = "ZoneRedundancyNotSupported"
+	VersionNotSupportedReason          = "VersionNotSupported"
+	SkuNotSupportedReason              = "SkuNotSupported"
+	EditionNotSupportedReason          = "EditionNotSupported"
+	ServiceObjectiveNotSupportedReason = "ServiceObjectiveNotSupported"
+	CapabilityRestrictedReason         = "CapabilityRestricted"
+	CapabilityDisabledReason           = "CapabilityDisabled"
+	SkuNotOfferedReason                = "SkuNotOffered"
+	PreviewVersionReason               = "PreviewVersion"
+	ProviderNotRegisteredReason        = "NotRegistered"
+	ProviderRegisteringReason          = "ProviderRegistering"
+	InsufficientQuotaReason            = "InsufficientQuota"
+	QuotaUnverifiedReason              = "QuotaUnverified"
+	BlockedByPolicyReason              = "BlockedByPolicy"
+	ResourceTypeNotFoundReason         = "ResourceTypeNotFound"
+	ApiVersionNotSupportedReason       = "ApiVersionNotSupported"
+	CancelledReason                    = "Cancelled"
+)
+
+type VerificationStatus string
+
+const (
+	StatusSupported   VerificationStatus = "supported"
+	StatusUnsupported VerificationStatus = "unsupported"
+	StatusDegraded    VerificationStatus = "degraded"
+	StatusUnknown     VerificationStatus = "unknown"
+)
+
+// VerificationResult is the outcome of verifying a service in a single location.
+type VerificationResult struct {
+	Service string `json:"service" yaml:"service"`
+	// The subscription the service is verified in, set when several subscriptions are verified
+	Subscription *AzureSubscription `json:"subscription,omitempty" yaml:"subscription,omitempty"`
+	Location     *AzureLocation     `json:"location" yaml:"location"`
+	Status       VerificationStatus `json:"status" yaml:"status"`
+	Features     map[string]bool    `json:"features,omitempty" yaml:"features,omitempty"`
+	Zones        []string           `json:"zones,omitempty" yaml:"zones,omitempty"`
+	ApiVersions  []string           `json:"apiVersions,omitempty" yaml:"apiVersions,omitempty"`
+	Quotas       []*QuotaUsage      `json:"quotas,omitempty" yaml:"quotas,omitempty"`
+	ReasonCode   string             `json:"reasonCode,omitempty" yaml:"reasonCode,omitempty"`
+	Evidence     string             `json:"evidence,omitempty" yaml:"evidence,omitempty"`
+}
+
+type VerificationResultList struct {
+	Value []*VerificationResult
+}
+
+func NewSupportedResult(service string, location *AzureLocation, evidence string) *VerificationResult {
+	return &VerificationResult{
+		Service:  service,
+		Location: location,
+		Status:   StatusSupported,
+		Features: map[string]bool{},
+		Evidence: evidence,
+	}
+}
+
+func NewUnsupportedResult(service string, location *AzureLocation, reasonCode string, evidence string) *VerificationResult {
+	return &VerificationResult{
+		Service:    service,
+		Location:   location,
+		Status:     StatusUnsupported,
+		Features:   map[string]bool{},
+		ReasonCode: reasonCode,
+		Evidence:   evidence,
+	}
+}
+
+func NewUnknownResult(service string, location *AzureLocation, reasonCode string, evidence string) *VerificationResult {
+	return &VerificationResult{
+		Service:    service,
+		Location:   location,
+		Status:     StatusUnknown,
+		Features:   map[string]bool{},
+		ReasonCode: reasonCode,
+		Evidence:   evidence,
+	}
+}
+
+// NewCancelledResult returns the result of a location that wasn't verified because the verification was cancelled,
+// e.g. with Ctrl-C or when the timeout elapsed. The context error tells why.
+func NewCancelledResult(ctx context.Context, service string, location *AzureLocation) *VerificationResult {
+	return NewUnknownResult(service, location, CancelledReason, fmt.Sprintf("the verification was cancelled: %s", context.Cause(ctx)))
+}
+
+// NewCancelledResults returns the cancelled results of all the locations, when the verification was cancelled
+// before the service was verified in any of them.
+func NewCancelledResults(ctx context.Context, service string, locations *AzureLocationList) *VerificationResultList {
+	results := &VerificationResultList{
+		Value: []*VerificationResult{},
+	}
+
+	for _, location := range locations.Value {
+		results.Value = append(results.Value, NewCancelledResult(ctx, service, location))
+	}
+	return results
+}
+
+// IsDeployable returns true if the service can be deployed to the location, possibly with reduced capabilities.
+func (r *VerificationResult) IsDeployable() bool {
+	return r.Status == StatusSupported || r.Status == StatusDegraded
+}
+
+func (r *VerificationResult) HasFeature(feature string) bool {
+	return r.Features[feature]
+}
+
+// FeatureString returns "true" or "false" depending on whether the feature is available.
+func (r *VerificationResult) FeatureString(feature string) string {
+	return strconv.FormatBool(r.HasFeature(feature))
+}
+
+// DeployableLocations returns the locations the service can be deployed to.
+// If features are provided, only the locations that support all of them are returned.
+func (list *VerificationResultList) DeployableLocations(features ...string) *AzureLocationList {
+	locations := &AzureLocationList{
+		Value: []*AzureLocation{},
+	}
+
+	for _, result := range list.Value {
+		if !result.IsDeployable() {
+			continue
+		}
+
+		supported := true
+		for _, feature := range features {
+			if !result.HasFeature(feature) {
+				supported = false
+				break
+			}
+		}
+
+		if supported {
+			locations.Value = append(locations.Value, result.Location)
+		}
+	}
+
+	return locations
+}
+
+// Append adds the results of the other list to this list.
+func (list *VerificationResultList) Append(other *VerificationResultList) {
+	list.Value = append(list.Value, other.Value...)
+}
+
+// CancelDeployable marks the deployable locations as unknown, when the verification was cancelled before the step
+// that verifies them, e.g. the evaluation of the Azure Policy assignments, was done.
+func (list *VerificationResultList) CancelDeployable(ctx context.Context, step string) {
+	for _, result := range list.Value {
+		if result.IsDeployable() {
+			result.Status = StatusUnknown
+			result.ReasonCode = CancelledReason
+			result.Evidence = fmt.Sprintf("the verification was cancelled before %s: %s", step, context.Cause(ctx))
+		}
+	}
+}
+
+// RequireFeature marks the deployable locations that don't support the feature as unsupported.
+func (list *VerificationResultList) RequireFeature(feature string, reasonCode string) {
+	for _, result := range list.Value {
+		if result.IsDeployable() && !result.HasFeature(feature) {
+			result.Status = StatusUnsupported
+			result.ReasonCode = reasonCode
+			result.Evidence = fmt.Sprintf("%s is not available in this location", feature)
+		}
+	}
+}
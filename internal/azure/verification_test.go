@@ -0,0 +1,156 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerificationResultList_DeployableLocations(t *testing.T) {
+	eastus := &AzureLocation{Name: "eastus", DisplayName: "East US"}
+	westus := &AzureLocation{Name: "westus", DisplayName: "West US"}
+	norway := &AzureLocation{Name: "norway", DisplayName: "Norway"}
+	centralus := &AzureLocation{Name: "centralus", DisplayName: "Central US"}
+
+	ha := NewSupportedResult(PostgresqlService, eastus, "")
+	ha.Features[HighAvailabilityFeature] = true
+
+	degraded := NewSupportedResult(PostgresqlService, centralus, "")
+	degraded.Status = StatusDegraded
+
+	list := &VerificationResultList{
+		Value: []*VerificationResult{
+			ha,
+			NewSupportedResult(PostgresqlService, westus, ""),
+			NewUnsupportedResult(PostgresqlService, norway, NoCapabilitiesReason, ""),
+			degraded,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		features []string
+		want     []string
+	}{
+		{
+			name: "Test deployable locations",
+			want: []string{"eastus", "westus", "centralus"},
+		},
+		{
+			name:     "Test deployable locations with HA",
+			features: []string{HighAvailabilityFeature},
+			want:     []string{"eastus"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := list.DeployableLocations(tt.features...)
+			if len(got.Value) != len(tt.want) {
+				t.Fatalf("VerificationResultList.DeployableLocations() = %v, want %v", got.Value, tt.want)
+			}
+			for i, location := range got.Value {
+				if location.Name != tt.want[i] {
+					t.Errorf("VerificationResultList.DeployableLocations()[%d] = %s, want %s", i, location.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewCancelledResults(t *testing.T) {
+	locations := &AzureLocationList{
+		Value: []*AzureLocation{
+			{Name: "eastus", DisplayName: "East US"},
+			{Name: "westus", DisplayName: "West US"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		cause        error
+		wantEvidence string
+	}{
+		{
+			name:         "Test interrupted",
+			cause:        errors.New("received interrupt"),
+			wantEvidence: "received interrupt",
+		},
+		{
+			name:         "Test cancelled without cause",
+			wantEvidence: context.Canceled.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancelCause(context.Background())
+			cancel(tt.cause)
+
+			got := NewCancelledResults(ctx, RedisService, locations)
+			if len(got.Value) != len(locations.Value) {
+				t.Fatalf("NewCancelledResults() = %d results, want %d", len(got.Value), len(locations.Value))
+			}
+			for i, result := range got.Value {
+				if result.Location != locations.Value[i] || result.Status != StatusUnknown || result.ReasonCode != CancelledReason {
+					t.Errorf("NewCancelledResults()[%d] = %+v, want an unknown %s result of %s", i, result, CancelledReason, locations.Value[i].Name)
+				}
+				if !strings.Contains(result.Evidence, tt.wantEvidence) {
+					t.Errorf("NewCancelledResults()[%d] evidence = %s, want %s", i, result.Evidence, tt.wantEvidence)
+				}
+				if result.IsDeployable() {
+					t.Errorf("NewCancelledResults()[%d] is deployable", i)
+				}
+			}
+		})
+	}
+}
+
+func TestVerificationResultList_CancelDeployable(t *testing.T) {
+	eastus := &AzureLocation{Name: "eastus", DisplayName: "East US"}
+
+	degraded := NewSupportedResult(RedisService, eastus, "")
+	degraded.Status = StatusDegraded
+
+	tests := []struct {
+		name           string
+		result         *VerificationResult
+		wantStatus     VerificationStatus
+		wantReasonCode string
+	}{
+		{
+			name:           "Test supported location",
+			result:         NewSupportedResult(RedisService, eastus, ""),
+			wantStatus:     StatusUnknown,
+			wantReasonCode: CancelledReason,
+		},
+		{
+			name:           "Test degraded location",
+			result:         degraded,
+			wantStatus:     StatusUnknown,
+			wantReasonCode: CancelledReason,
+		},
+		{
+			name:           "Test unsupported location",
+			result:         NewUnsupportedResult(RedisService, eastus, LocationNotOfferedReason, ""),
+			wantStatus:     StatusUnsupported,
+			wantReasonCode: LocationNotOfferedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancelCause(context.Background())
+			cancel(errors.New("received interrupt"))
+
+			list := &VerificationResultList{Value: []*VerificationResult{tt.result}}
+			list.CancelDeployable(ctx, "the policy assignments were evaluated")
+
+			got := list.Value[0]
+			if got.Status != tt.wantStatus || got.ReasonCode != tt.wantReasonCode {
+				t.Errorf("VerificationResultList.CancelDeployable() = %s %s, want %s %s", got.Status, got.ReasonCode, tt.wantStatus, tt.wantReasonCode)
+			}
+			if tt.wantReasonCode == CancelledReason && !strings.Contains(got.Evidence, "received interrupt") {
+				t.Errorf("VerificationResultList.CancelDeployable() evidence = %s, want the cause", got.Evidence)
+			}
+		})
+	}
+}
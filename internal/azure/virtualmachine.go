@@ -0,0 +1,161 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+)
+
+const virtualMachinesResourceType = "virtualMachines"
+
+type AzureVirtualMachineSku struct {
+	session *Session
+}
+
+func NewAzureVirtualMachineSku(session *Session) *AzureVirtualMachineSku {
+	return &AzureVirtualMachineSku{
+		session: session,
+	}
+}
+
+// GetVirtualMachineSkuLocations verifies the virtual machine size (e.g. Standard_D4s_v5) is offered in the locations
+// and is not restricted for the subscription. The zones the size can be deployed to are reported as well.
+func (a *AzureVirtualMachineSku) GetVirtualMachineSkuLocations(ctx context.Context, locations *AzureLocationList, size string) (*VerificationResultList, error) {
+	skusByLocation, errs, err := a.getVirtualMachineSkus(ctx, locations)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &VerificationResultList{
+		Value: []*VerificationResult{},
+	}
+
+	for i, location := range locations.Value {
+		if errs[i] != nil {
+			if ctx.Err() != nil {
+				results.Value = append(results.Value, NewCancelledResult(ctx, VirtualMachineSkuService, location))
+			} else if azureErr, ok := errs[i].(*azcore.ResponseError); ok {
+				results.Value = append(results.Value, NewUnknownResult(VirtualMachineSkuService, location, azureErr.ErrorCode, azureErr.Error()))
+			} else {
+				results.Value = append(results.Value, NewUnknownResult(VirtualMachineSkuService, location, RequestFailedReason, errs[i].Error()))
+			}
+			continue
+		}
+
+		results.Value = append(results.Value, evaluateVirtualMachineSku(location, skusByLocation[i], size))
+	}
+
+	return results, nil
+}
+
+// getVirtualMachineSkus returns the virtual machine SKUs of every location, in the same order as the locations.
+// The error of a location is returned in errs so that the other locations can still be verified.
+func (a *AzureVirtualMachineSku) getVirtualMachineSkus(ctx context.Context, locations *AzureLocationList) ([][]*armcompute.ResourceSKU, []error, error) {
+	client, err := a.session.resourceSkusClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The following is used to store the SKUs from our go routine.
+	skus := make([][]*armcompute.ResourceSKU, len(locations.Value))
+	errs := make([]error, len(locations.Value))
+
+	a.session.limiter.forEachLocation(locations, func(idx int, azureLocation *AzureLocation) {
+		log.Printf("Getting virtual machine SKUs for location %s", azureLocation.DisplayName)
+		skus[idx], errs[idx] = cached(a.session, "compute/virtualMachineSkus", azureLocation.Name, skusCacheTTL, func() ([]*armcompute.ResourceSKU, error) {
+			locationSkus := []*armcompute.ResourceSKU{}
+			pager := client.NewListPager(&armcompute.ResourceSKUsClientListOptions{
+				Filter: to.Ptr(fmt.Sprintf("location eq '%s'", azureLocation.Name)),
+			})
+			for pager.More() {
+				nextResult, err := pager.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, sku := range nextResult.Value {
+					if sku.ResourceType != nil && *sku.ResourceType == virtualMachinesResourceType {
+						locationSkus = append(locationSkus, sku)
+					}
+				}
+			}
+			return locationSkus, nil
+		})
+	})
+
+	return skus, errs, nil
+}
+
+// evaluateVirtualMachineSku verifies the virtual machine size can be deployed to the location.
+// A restriction of the whole location makes the size unsupported. A restriction of some of the zones
+// degrades the size, it can still be deployed to the other zones or without a zone.
+func evaluateVirtualMachineSku(location *AzureLocation, skus []*armcompute.ResourceSKU, size string) *VerificationResult {
+	var sku *armcompute.ResourceSKU
+	for _, s := range skus {
+		if s.Name != nil && strings.EqualFold(*s.Name, size) {
+			sku = s
+			break
+		}
+	}
+
+	if sku == nil {
+		return NewUnsupportedResult(VirtualMachineSkuService, location, SkuNotOfferedReason, fmt.Sprintf("%s is not offered in this location", size))
+	}
+
+	var zones []string
+	for _, info := range sku.LocationInfo {
+		if info.Location != nil && strings.EqualFold(*info.Location, location.Name) {
+			zones = append(zones, toValues(info.Zones)...)
+		}
+	}
+
+	var restrictedZones []string
+	var zoneReasonCode string
+	for _, restriction := range sku.Restrictions {
+		if restriction.Type == nil {
+			continue
+		}
+
+		reasonCode := ""
+		if restriction.ReasonCode != nil {
+			reasonCode = string(*restriction.ReasonCode)
+		}
+
+		switch *restriction.Type {
+		case armcompute.ResourceSKURestrictionsTypeLocation:
+			return NewUnsupportedResult(VirtualMachineSkuService, location, reasonCode, fmt.Sprintf("%s is restricted in this location for the subscription", size))
+		case armcompute.ResourceSKURestrictionsTypeZone:
+			if restriction.RestrictionInfo != nil {
+				restrictedZones = append(restrictedZones, toValues(restriction.RestrictionInfo.Zones)...)
+			}
+			zoneReasonCode = reasonCode
+		}
+	}
+
+	availableZones := []string{}
+	for _, zone := range zones {
+		if !slices.Contains(restrictedZones, zone) {
+			availableZones = append(availableZones, zone)
+		}
+	}
+	slices.Sort(availableZones)
+
+	result := NewSupportedResult(VirtualMachineSkuService, location, fmt.Sprintf("%s is offered in this location", size))
+	result.Zones = availableZones
+	result.Features[ZoneRedundancyFeature] = len(availableZones) > 1
+
+	if len(restrictedZones) > 0 {
+		slices.Sort(restrictedZones)
+		result.Status = StatusDegraded
+		result.ReasonCode = zoneReasonCode
+		result.Evidence = fmt.Sprintf("%s is restricted in zones %s for the subscription", size, strings.Join(restrictedZones, ", "))
+	}
+
+	return result
+}
@@ -0,0 +1,82 @@
+package azure
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+)
+
+func TestEvaluateVirtualMachineSku(t *testing.T) {
+	location := &AzureLocation{Name: "eastus2", DisplayName: "East US 2"}
+
+	newSku := func(name string, restrictions ...*armcompute.ResourceSKURestrictions) *armcompute.ResourceSKU {
+		return &armcompute.ResourceSKU{
+			Name:         to.Ptr(name),
+			ResourceType: to.Ptr(virtualMachinesResourceType),
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
+				{
+					Location: to.Ptr("eastus2"),
+					Zones:    []*string{to.Ptr("3"), to.Ptr("1"), to.Ptr("2")},
+				},
+			},
+			Restrictions: restrictions,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		sku        *armcompute.ResourceSKU
+		wantStatus VerificationStatus
+		wantReason string
+		wantZones  []string
+	}{
+		{
+			name:       "Test unrestricted size",
+			sku:        newSku("Standard_D4s_v5"),
+			wantStatus: StatusSupported,
+			wantZones:  []string{"1", "2", "3"},
+		},
+		{
+			name: "Test size restricted in the location",
+			sku: newSku("Standard_D4s_v5", &armcompute.ResourceSKURestrictions{
+				Type:       to.Ptr(armcompute.ResourceSKURestrictionsTypeLocation),
+				ReasonCode: to.Ptr(armcompute.ResourceSKURestrictionsReasonCodeNotAvailableForSubscription),
+			}),
+			wantStatus: StatusUnsupported,
+			wantReason: "NotAvailableForSubscription",
+		},
+		{
+			name: "Test size restricted in a zone",
+			sku: newSku("Standard_D4s_v5", &armcompute.ResourceSKURestrictions{
+				Type:            to.Ptr(armcompute.ResourceSKURestrictionsTypeZone),
+				ReasonCode:      to.Ptr(armcompute.ResourceSKURestrictionsReasonCodeNotAvailableForSubscription),
+				RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{Zones: []*string{to.Ptr("2")}},
+			}),
+			wantStatus: StatusDegraded,
+			wantReason: "NotAvailableForSubscription",
+			wantZones:  []string{"1", "3"},
+		},
+		{
+			name:       "Test size not offered",
+			sku:        newSku("Standard_E4s_v5"),
+			wantStatus: StatusUnsupported,
+			wantReason: SkuNotOfferedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateVirtualMachineSku(location, []*armcompute.ResourceSKU{tt.sku}, "standard_d4s_v5")
+			if got.Status != tt.wantStatus {
+				t.Errorf("evaluateVirtualMachineSku() status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if got.ReasonCode != tt.wantReason {
+				t.Errorf("evaluateVirtualMachineSku() reason = %s, want %s", got.ReasonCode, tt.wantReason)
+			}
+			if len(tt.wantZones) > 0 && !slices.Equal(got.Zones, tt.wantZones) {
+				t.Errorf("evaluateVirtualMachineSku() zones = %v, want %v", got.Zones, tt.wantZones)
+			}
+		})
+	}
+}
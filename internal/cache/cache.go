@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The directory of the cache in the user cache directory
+const dirName = "azure-resource-verifier"
+
+// The extension of the files of the cache entries
+const entryExtension = ".json"
+
+// Cache stores the responses of the Azure APIs on disk, e.g. the locations of a subscription or the capabilities
+// of a service in a region, until their time to live expires.
+type Cache struct {
+	dir string
+	// The entries are neither read nor written
+	disabled bool
+	// The entries are written but not read, i.e. the responses are fetched again
+	refresh bool
+	now     func() time.Time
+}
+
+type Options struct {
+	// The directory of the entries. Defaults to the azure-resource-verifier directory of the user cache directory
+	Dir      string
+	Disabled bool
+	Refresh  bool
+}
+
+// New returns the cache of the options.
+func New(options Options) (*Cache, error) {
+	dir := options.Dir
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the user cache directory: %w", err)
+		}
+		dir = filepath.Join(userCacheDir, dirName)
+	}
+
+	return &Cache{
+		dir:      dir,
+		disabled: options.Disabled,
+		refresh:  options.Refresh,
+		now:      time.Now,
+	}, nil
+}
+
+// Dir returns the directory of the entries.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Key returns the key of an entry from its parts, e.g. the cloud, the subscription, the API and the region.
+func Key(parts ...string) string {
+	return strings.ToLower(strings.Join(parts, "/"))
+}
+
+// entry is the file of a cached response
+type entry struct {
+	Key     string          `json:"key"`
+	Expires time.Time       `json:"expires"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// Fetch returns the cached value of the key if it has not expired, or the value of the fetch function.
+// The fetched value is cached for the time to live. The errors of the fetch function are not cached,
+// and the errors of the cache only make the value be fetched again. A nil cache is disabled.
+func Fetch[T any](c *Cache, key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	if c == nil {
+		return fetch()
+	}
+
+	if !c.disabled && !c.refresh {
+		var value T
+		if ok := c.read(key, &value); ok {
+			return value, nil
+		}
+	}
+
+	value, err := fetch()
+	if err != nil || c.disabled {
+		return value, err
+	}
+
+	if err := c.write(key, ttl, value); err != nil {
+		log.Printf("Failed to cache %s: %s", key, err)
+	}
+	return value, nil
+}
+
+func (c *Cache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+entryExtension)
+}
+
+func (c *Cache) read(key string, value any) bool {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+
+	e := &entry{}
+	if err := json.Unmarshal(data, e); err != nil || e.Key != key || !c.now().Before(e.Expires) {
+		return false
+	}
+
+	if err := json.Unmarshal(e.Value, value); err != nil {
+		return false
+	}
+
+	log.Printf("Using the cached %s", key)
+	return true
+}
+
+func (c *Cache) write(key string, ttl time.Duration, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	data, err = json.Marshal(&entry{Key: key, Expires: c.now().Add(ttl), Value: data})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+
+	// The entry is renamed into place so that concurrent runs never read a partial entry
+	file, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), c.path(key))
+}
+
+// Info describes the entries of the cache.
+type Info struct {
+	Dir     string `json:"dir" yaml:"dir"`
+	Entries int    `json:"entries" yaml:"entries"`
+	Expired int    `json:"expired" yaml:"expired"`
+	Size    int64  `json:"size" yaml:"size"`
+}
+
+// Info returns the number of entries of the cache, the number of expired entries and their size in bytes.
+func (c *Cache) Info() (*Info, error) {
+	info := &Info{Dir: c.dir}
+
+	err := c.walk(func(path string, size int64) error {
+		info.Entries++
+		info.Size += size
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		e := &entry{}
+		if err := json.Unmarshal(data, e); err != nil || !c.now().Before(e.Expires) {
+			info.Expired++
+		}
+		return nil
+	})
+
+	return info, err
+}
+
+// Clear removes all the entries of the cache and returns their number.
+func (c *Cache) Clear() (int, error) {
+	removed := 0
+	err := c.walk(func(path string, _ int64) error {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+
+	return removed, err
+}
+
+// walk calls the function for the file of each entry of the cache. A cache without a directory has no entries.
+func (c *Cache) walk(fn func(path string, size int64) error) error {
+	files, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the cache %s: %w", c.dir, err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != entryExtension {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			return err
+		}
+
+		if err := fn(filepath.Join(c.dir, file.Name()), info.Size()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestCache(t *testing.T, options Options) (*Cache, *time.Time) {
+	t.Helper()
+	if options.Dir == "" {
+		options.Dir = t.TempDir()
+	}
+
+	c, err := New(options)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestFetch(t *testing.T) {
+	c, now := newTestCache(t, Options{})
+	key := Key("https://management.azure.com", "0000", "postgresql/capabilities", "eastus")
+
+	calls := 0
+	fetch := func() ([]string, error) {
+		calls++
+		return []string{"16", "15"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := Fetch(c, key, time.Hour, fetch)
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if len(got) != 2 || got[0] != "16" {
+			t.Errorf("Fetch() = %v, want [16 15]", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Fetch() fetched %d times, want 1", calls)
+	}
+
+	// The entry expires after its time to live
+	*now = now.Add(2 * time.Hour)
+	if _, err := Fetch(c, key, time.Hour, fetch); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Fetch() after expiry fetched %d times, want 2", calls)
+	}
+
+	// The other keys are not shared
+	if _, err := Fetch(c, Key("https://management.azure.com", "0000", "postgresql/capabilities", "westus"), time.Hour, fetch); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Fetch() of another key fetched %d times, want 3", calls)
+	}
+}
+
+func TestFetch_Options(t *testing.T) {
+	dir := t.TempDir()
+	key := Key("locations")
+
+	tests := []struct {
+		name      string
+		options   Options
+		wantCalls int
+	}{
+		{
+			name:      "Test no cache",
+			options:   Options{Dir: dir, Disabled: true},
+			wantCalls: 2,
+		},
+		{
+			name:      "Test refresh",
+			options:   Options{Dir: dir, Refresh: true},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestCache(t, tt.options)
+
+			calls := 0
+			for i := 0; i < 2; i++ {
+				if _, err := Fetch(c, key, time.Hour, func() (int, error) { calls++; return calls, nil }); err != nil {
+					t.Fatalf("Fetch() error = %v", err)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Fetch() fetched %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+
+	// The refreshed entry is read without --refresh, the disabled cache wrote nothing before it
+	c, _ := newTestCache(t, Options{Dir: dir})
+	got, err := Fetch(c, key, time.Hour, func() (int, error) { return 0, errors.New("not cached") })
+	if err != nil || got != 2 {
+		t.Errorf("Fetch() = %d, %v, want the refreshed 2", got, err)
+	}
+}
+
+func TestFetch_Error(t *testing.T) {
+	c, _ := newTestCache(t, Options{})
+	key := Key("providers", "Microsoft.Cache")
+
+	if _, err := Fetch(c, key, time.Hour, func() (string, error) { return "", errors.New("throttled") }); err == nil {
+		t.Fatalf("Fetch() error = nil, want an error")
+	}
+
+	got, err := Fetch(c, key, time.Hour, func() (string, error) { return "Microsoft.Cache", nil })
+	if err != nil || got != "Microsoft.Cache" {
+		t.Errorf("Fetch() = %s, %v, want the error not to be cached", got, err)
+	}
+}
+
+func TestCache_InfoAndClear(t *testing.T) {
+	c, now := newTestCache(t, Options{})
+
+	for _, key := range []string{Key("a"), Key("b"), Key("c")} {
+		ttl := time.Hour
+		if key == Key("c") {
+			ttl = time.Minute
+		}
+		if _, err := Fetch(c, key, ttl, func() (string, error) { return key, nil }); err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+	}
+	*now = now.Add(10 * time.Minute)
+
+	info, err := c.Info()
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if info.Entries != 3 || info.Expired != 1 || info.Size == 0 {
+		t.Errorf("Info() = %+v, want 3 entries and 1 expired", info)
+	}
+
+	removed, err := c.Clear()
+	if err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("Clear() = %d, want 3", removed)
+	}
+
+	if info, err := c.Info(); err != nil || info.Entries != 0 {
+		t.Errorf("Info() after Clear() = %+v, %v, want no entries", info, err)
+	}
+}
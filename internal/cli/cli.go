@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/cache"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -17,11 +20,29 @@ type AzureResourceVerifierCliError struct {
 }
 
 func (e *AzureResourceVerifierCliError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
 	return e.Err.Error()
 }
 
 func AzureClientWrapRunE(
-	runEFunc func(cmd *cobra.Command, args []string, cred *azidentity.DefaultAzureCredential, ctx context.Context) error,
+	runEFunc func(cmd *cobra.Command, args []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error,
+) func(cmd *cobra.Command, args []string) error {
+	return azureClientWrapRunE(runEFunc, NewCommandContext)
+}
+
+// AzureClientWrapInteractiveRunE is AzureClientWrapRunE for the interactive commands: the context is only cancelled
+// on SIGINT or SIGTERM, the command applies the --timeout with WithCommandTimeout once the choices are made.
+func AzureClientWrapInteractiveRunE(
+	runEFunc func(cmd *cobra.Command, args []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error,
+) func(cmd *cobra.Command, args []string) error {
+	return azureClientWrapRunE(runEFunc, NewSignalContext)
+}
+
+func azureClientWrapRunE(
+	runEFunc func(cmd *cobra.Command, args []string, cred azcore.TokenCredential, options azure.SessionOptions, ctx context.Context) error,
+	newContext func() (context.Context, context.CancelFunc),
 ) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		// Silence usage so we don't print the usage when an error occurs
@@ -31,17 +52,42 @@ func AzureClientWrapRunE(
 			return fmt.Errorf("error binding flags: %s", err)
 		}
 
-		cred, err := azidentity.NewDefaultAzureCredential(nil)
+		ctx, cancel := newContext()
+		defer cancel()
+
+		options, err := NewSessionOptions(ctx)
 		if err != nil {
 			return err
 		}
 
-		ctx := context.Background()
+		credentialOptions := NewCredentialOptions()
+		credentialOptions.Cloud = options.Cloud
 
-		return runEFunc(cmd, args, cred, ctx)
+		cred, err := NewCredential(credentialOptions)
+		if err != nil {
+			return CreateAzrErr("Error creating the credential", err)
+		}
+
+		if err := runEFunc(cmd, args, cred, options, ctx); err != nil {
+			return err
+		}
+
+		// The partial results of a cancelled command are rendered, but the command still fails
+		if ctx.Err() != nil {
+			return CancelledErr(ctx)
+		}
+		return nil
 	}
 }
 
+// NewCache returns the cache of the responses of the Azure APIs of the --no-cache and --refresh flags.
+func NewCache() (*cache.Cache, error) {
+	return cache.New(cache.Options{
+		Disabled: viper.GetBool("no-cache"),
+		Refresh:  viper.GetBool("refresh"),
+	})
+}
+
 func CreateAzrErr(msg string, err error) error {
 	return &AzureResourceVerifierCliError{Message: msg, Err: err}
 }
@@ -66,18 +112,18 @@ func ExitOnError(err error, userMessage string) {
 
 		// Check if it's an Azure Authentication Error
 		if azureErr, ok := err.(*azidentity.AuthenticationFailedError); ok {
-			message = "It looks like you're not authenticated. Please run `az login` and try again."
+			message = "It looks like you're not authenticated. Please run `az login`, or select the credential with --auth-mode, and try again. Run `azure-resource-verifier doctor` to diagnose the authentication."
 			details = azureErr.Error()
 		} else if azureErr, ok := err.(*azidentity.AuthenticationRequiredError); ok {
-			message = "It looks like you're not authenticated. Please run `az login` and try again."
+			message = "It looks like you're not authenticated. Please run `az login`, or select the credential with --auth-mode, and try again. Run `azure-resource-verifier doctor` to diagnose the authentication."
 			details = azureErr.Error()
 
 			/* TODO: credentialUnavailableError is not available in the current version of the SDK
 			} else if azureErr, ok := err.(*azidentity.credentialUnavailableError); ok {
-				message = "It looks like you're not authenticated. Please run `az login` and try again."
+				message = "It looks like you're not authenticated. Please run `az login`, or select the credential with --auth-mode, and try again. Run `azure-resource-verifier doctor` to diagnose the authentication."
 				details = azureErr.Error()
 			*/
-		} else {
+		} else if err != nil {
 			details = err.Error()
 		}
 
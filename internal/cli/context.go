@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/spf13/viper"
+)
+
+// NewSessionOptions returns the options of the sessions of a command: the cloud of the cloud flags, the cache of
+// the --no-cache and --refresh flags, the timeout of each request to the Azure Resource Manager API of the
+// --request-timeout flag, and the limit of the requests of the per-location verifications in flight of the
+// --concurrency flag, shared by the sessions.
+func NewSessionOptions(ctx context.Context) (azure.SessionOptions, error) {
+	// The concurrency can also come from the config file or the ARV_CONCURRENCY environment variable
+	concurrency := viper.GetInt("concurrency")
+	if concurrency < 1 {
+		return azure.SessionOptions{}, fmt.Errorf("invalid concurrency %d: expected at least 1", concurrency)
+	}
+
+	cloudConfiguration, err := NewCloudConfiguration(ctx)
+	if err != nil {
+		return azure.SessionOptions{}, CreateAzrErr("Error configuring the cloud", err)
+	}
+
+	c, err := NewCache()
+	if err != nil {
+		return azure.SessionOptions{}, CreateAzrErr("Error configuring the cache", err)
+	}
+
+	return azure.SessionOptions{
+		Cloud:          cloudConfiguration,
+		RequestTimeout: viper.GetDuration("request-timeout"),
+		Limiter:        azure.NewRequestLimiter(concurrency),
+		Cache:          c,
+	}, nil
+}
+
+// NewCommandContext returns the context of the Azure calls of a command. The context is cancelled on SIGINT
+// or SIGTERM, and when the --timeout elapses. A second signal terminates the process as usual.
+func NewCommandContext() (context.Context, context.CancelFunc) {
+	ctx, stop := NewSignalContext()
+
+	timeoutCtx, cancelTimeout := WithCommandTimeout(ctx)
+	return timeoutCtx, func() {
+		cancelTimeout()
+		stop()
+	}
+}
+
+// NewSignalContext returns a context cancelled on SIGINT or SIGTERM, without the --timeout. It is the context of the
+// interactive commands, whose --timeout starts once the choices are made. A second signal terminates the process as usual.
+func NewSignalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			cancel(fmt.Errorf("received %s", sig))
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// WithCommandTimeout returns a context cancelled when the --timeout elapses, or the context itself without a timeout.
+func WithCommandTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := viper.GetDuration("timeout")
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeoutCause(ctx, timeout, fmt.Errorf("timed out after %s", timeout.Round(time.Second)))
+}
+
+// CancelledErr returns the error of a command whose context was cancelled, after its partial results were rendered.
+func CancelledErr(ctx context.Context) error {
+	return CreateAzrErr("The verification was cancelled", fmt.Errorf("%w: the locations that were not verified are unknown (%s)", context.Cause(ctx), azure.CancelledReason))
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/spf13/viper"
+)
+
+// The modes of authentication to Azure
+const (
+	DefaultAuthMode            = "default"
+	AzureCliAuthMode           = "azcli"
+	EnvironmentAuthMode        = "env"
+	ManagedIdentityAuthMode    = "managed-identity"
+	WorkloadIdentityAuthMode   = "workload-identity"
+	DeviceCodeAuthMode         = "device-code"
+	InteractiveBrowserAuthMode = "interactive-browser"
+	ClientCertificateAuthMode  = "client-certificate"
+)
+
+// The environment variables of the Azure SDKs used to configure the client certificate credential
+const (
+	tenantIdVariable                  = "AZURE_TENANT_ID"
+	clientIdVariable                  = "AZURE_CLIENT_ID"
+	clientCertificatePathVariable     = "AZURE_CLIENT_CERTIFICATE_PATH"
+	clientCertificatePasswordVariable = "AZURE_CLIENT_CERTIFICATE_PASSWORD"
+)
+
+var CloudChoice = CliChoice{
+	Name:        "cloud",
+	Description: "The Azure cloud (AzurePublic, AzureUSGovernment, AzureChina or custom). The custom cloud requires --arm-endpoint",
+	Default:     azure.AzurePublicCloud,
+	Choices:     azure.Clouds,
+}
+
+var AuthModeChoice = CliChoice{
+	Name:        "auth-mode",
+	Description: "The mode of authentication to Azure (default, azcli, env, managed-identity, workload-identity, device-code, interactive-browser or client-certificate)",
+	Default:     DefaultAuthMode,
+	Choices: []string{
+		DefaultAuthMode,
+		AzureCliAuthMode,
+		EnvironmentAuthMode,
+		ManagedIdentityAuthMode,
+		WorkloadIdentityAuthMode,
+		DeviceCodeAuthMode,
+		InteractiveBrowserAuthMode,
+		ClientCertificateAuthMode,
+	},
+}
+
+type CredentialOptions struct {
+	// The mode of authentication, one of the AuthModeChoice choices
+	AuthMode string
+	// The Microsoft Entra tenant to authenticate in. Defaults to the tenant of the credential
+	TenantId string
+	// The client id of a user-assigned managed identity, a workload identity, an application or a service principal
+	ClientId string
+	// The path of the PEM or PKCS#12 certificate of the client-certificate mode
+	ClientCertificate string
+	// The cloud to authenticate in, i.e. its authority host. Defaults to the Azure public cloud
+	Cloud cloud.Configuration
+}
+
+// NewCredentialOptions returns the credential options of the --auth-mode, --tenant-id, --client-id and
+// --client-certificate flags.
+func NewCredentialOptions() CredentialOptions {
+	return CredentialOptions{
+		AuthMode:          viper.GetString(AuthModeChoice.Name),
+		TenantId:          viper.GetString("tenant-id"),
+		ClientId:          viper.GetString("client-id"),
+		ClientCertificate: viper.GetString("client-certificate"),
+	}
+}
+
+// NewCloudConfiguration returns the configuration of the cloud of the --cloud, --arm-endpoint, --arm-audience and
+// --authority-host flags, for the clients of the Azure Resource Manager API and the credential.
+// The cloud is validated here since it can also come from the config file or the ARV_CLOUD environment variable.
+func NewCloudConfiguration(ctx context.Context) (cloud.Configuration, error) {
+	cloudName := viper.GetString(CloudChoice.Name)
+	if !CloudChoice.IsValidChoice(cloudName) {
+		return cloud.Configuration{}, fmt.Errorf("invalid cloud choice: %s", cloudName)
+	}
+
+	return azure.NewCloudConfiguration(ctx, azure.CloudOptions{
+		Name:          cloudName,
+		ArmEndpoint:   viper.GetString("arm-endpoint"),
+		Audience:      viper.GetString("arm-audience"),
+		AuthorityHost: viper.GetString("authority-host"),
+	})
+}
+
+// NewCredential returns the credential of the mode of authentication.
+func NewCredential(options CredentialOptions) (azcore.TokenCredential, error) {
+	clientOptions := azcore.ClientOptions{Cloud: options.Cloud}
+
+	switch options.AuthMode {
+	case DefaultAuthMode, "":
+		return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{ClientOptions: clientOptions, TenantID: options.TenantId})
+	case AzureCliAuthMode:
+		return azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{TenantID: options.TenantId})
+	case EnvironmentAuthMode:
+		// The environment credential only reads the tenant from the environment
+		if options.TenantId != "" {
+			return nil, fmt.Errorf("--tenant-id is not supported with the %s auth mode, set %s instead", EnvironmentAuthMode, tenantIdVariable)
+		}
+		return azidentity.NewEnvironmentCredential(&azidentity.EnvironmentCredentialOptions{ClientOptions: clientOptions})
+	case ManagedIdentityAuthMode:
+		managedIdentityOptions := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
+		if options.ClientId != "" {
+			managedIdentityOptions.ID = azidentity.ClientID(options.ClientId)
+		}
+		return azidentity.NewManagedIdentityCredential(managedIdentityOptions)
+	case WorkloadIdentityAuthMode:
+		return azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientOptions: clientOptions,
+			TenantID:      options.TenantId,
+			ClientID:      options.ClientId,
+		})
+	case DeviceCodeAuthMode:
+		return azidentity.NewDeviceCodeCredential(&azidentity.DeviceCodeCredentialOptions{
+			ClientOptions: clientOptions,
+			TenantID:      options.TenantId,
+			ClientID:      options.ClientId,
+			// The instructions are written to stderr so the output stays parsable
+			UserPrompt: func(_ context.Context, message azidentity.DeviceCodeMessage) error {
+				fmt.Fprintln(os.Stderr, message.Message)
+				return nil
+			},
+		})
+	case InteractiveBrowserAuthMode:
+		return azidentity.NewInteractiveBrowserCredential(&azidentity.InteractiveBrowserCredentialOptions{
+			ClientOptions: clientOptions,
+			TenantID:      options.TenantId,
+			ClientID:      options.ClientId,
+		})
+	case ClientCertificateAuthMode:
+		return newClientCertificateCredential(options)
+	default:
+		return nil, fmt.Errorf("invalid auth mode choice: %s", options.AuthMode)
+	}
+}
+
+// The maximum duration of the probe of the managed identity endpoint by the default auth mode. Off Azure,
+// the endpoint doesn't answer and DefaultAzureCredential moves on to the next credential after a short probe too.
+const managedIdentityProbeTimeout = 2 * time.Second
+
+// ChainedCredential is a credential of the chain tried by the default auth mode. Err is set if the credential
+// can't be created, e.g. when its environment variables are not set. Timeout, if set, limits the first token
+// request of the credential, so that an unreachable endpoint doesn't hold up the next credentials.
+type ChainedCredential struct {
+	Name       string
+	Credential azcore.TokenCredential
+	Err        error
+	Timeout    time.Duration
+}
+
+// NewCredentialChain returns the credentials tried in order by the auth mode. The default auth mode tries the
+// credentials of DefaultAzureCredential, the other auth modes have a single credential.
+func NewCredentialChain(options CredentialOptions) []*ChainedCredential {
+	if options.AuthMode != DefaultAuthMode && options.AuthMode != "" {
+		cred, err := NewCredential(options)
+		if err != nil {
+			return []*ChainedCredential{{Name: options.AuthMode, Err: err}}
+		}
+		return []*ChainedCredential{{Name: options.AuthMode, Credential: cred}}
+	}
+
+	clientOptions := azcore.ClientOptions{Cloud: options.Cloud}
+
+	chain := []*ChainedCredential{}
+	add := func(name string, cred azcore.TokenCredential, err error) {
+		if err != nil {
+			cred = nil
+		}
+		chain = append(chain, &ChainedCredential{Name: name, Credential: cred, Err: err})
+	}
+
+	// The order of DefaultAzureCredential
+	if options.TenantId == "" {
+		cred, err := azidentity.NewEnvironmentCredential(&azidentity.EnvironmentCredentialOptions{ClientOptions: clientOptions})
+		add(EnvironmentAuthMode, cred, err)
+	} else {
+		add(EnvironmentAuthMode, nil, fmt.Errorf("not tried with --tenant-id"))
+	}
+
+	workloadIdentity, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{ClientOptions: clientOptions, TenantID: options.TenantId, ClientID: options.ClientId})
+	add(WorkloadIdentityAuthMode, workloadIdentity, err)
+
+	managedIdentityOptions := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
+	if clientId := defaultEnv(options.ClientId, clientIdVariable); clientId != "" {
+		managedIdentityOptions.ID = azidentity.ClientID(clientId)
+	}
+	managedIdentity, err := azidentity.NewManagedIdentityCredential(managedIdentityOptions)
+	add(ManagedIdentityAuthMode, managedIdentity, err)
+	chain[len(chain)-1].Timeout = managedIdentityProbeTimeout
+
+	azureCli, err := azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{TenantID: options.TenantId})
+	add(AzureCliAuthMode, azureCli, err)
+
+	azureDeveloperCli, err := azidentity.NewAzureDeveloperCLICredential(&azidentity.AzureDeveloperCLICredentialOptions{TenantID: options.TenantId})
+	add("azd", azureDeveloperCli, err)
+
+	return chain
+}
+
+// newClientCertificateCredential returns the credential of a service principal with a certificate. The tenant, the client id
+// and the certificate default to the AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_CERTIFICATE_PATH environment variables.
+func newClientCertificateCredential(options CredentialOptions) (azcore.TokenCredential, error) {
+	tenantId := defaultEnv(options.TenantId, tenantIdVariable)
+	clientId := defaultEnv(options.ClientId, clientIdVariable)
+	certificatePath := defaultEnv(options.ClientCertificate, clientCertificatePathVariable)
+
+	if tenantId == "" || clientId == "" || certificatePath == "" {
+		return nil, errors.New("the client-certificate auth mode requires --tenant-id, --client-id and --client-certificate")
+	}
+
+	data, err := os.ReadFile(certificatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the client certificate %s: %w", certificatePath, err)
+	}
+
+	var password []byte
+	if value := os.Getenv(clientCertificatePasswordVariable); value != "" {
+		password = []byte(value)
+	}
+
+	certificates, key, err := azidentity.ParseCertificates(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the client certificate %s: %w", certificatePath, err)
+	}
+
+	return azidentity.NewClientCertificateCredential(tenantId, clientId, certificates, key, &azidentity.ClientCertificateCredentialOptions{
+		ClientOptions: azcore.ClientOptions{Cloud: options.Cloud},
+	})
+}
+
+func defaultEnv(value string, variable string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(variable)
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/spf13/viper"
+)
+
+func TestNewCredential(t *testing.T) {
+	t.Setenv(tenantIdVariable, "")
+	t.Setenv(clientIdVariable, "")
+	t.Setenv(clientCertificatePathVariable, "")
+
+	invalidCertificate := filepath.Join(t.TempDir(), "client.pem")
+	if err := os.WriteFile(invalidCertificate, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("failed to write the certificate: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		options CredentialOptions
+		wantErr bool
+	}{
+		{
+			name:    "Test default",
+			options: CredentialOptions{},
+		},
+		{
+			name:    "Test azcli with tenant",
+			options: CredentialOptions{AuthMode: AzureCliAuthMode, TenantId: "00000000-0000-0000-0000-000000000000"},
+		},
+		{
+			name:    "Test user-assigned managed identity",
+			options: CredentialOptions{AuthMode: ManagedIdentityAuthMode, ClientId: "00000000-0000-0000-0000-000000000000"},
+		},
+		{
+			name:    "Test env with tenant",
+			options: CredentialOptions{AuthMode: EnvironmentAuthMode, TenantId: "00000000-0000-0000-0000-000000000000"},
+			wantErr: true,
+		},
+		{
+			name:    "Test client certificate without client id",
+			options: CredentialOptions{AuthMode: ClientCertificateAuthMode, TenantId: "00000000-0000-0000-0000-000000000000", ClientCertificate: invalidCertificate},
+			wantErr: true,
+		},
+		{
+			name:    "Test client certificate with an invalid certificate",
+			options: CredentialOptions{AuthMode: ClientCertificateAuthMode, TenantId: "00000000-0000-0000-0000-000000000000", ClientId: "app", ClientCertificate: invalidCertificate},
+			wantErr: true,
+		},
+		{
+			name:    "Test invalid auth mode",
+			options: CredentialOptions{AuthMode: "password"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cred, err := NewCredential(tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCredential() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cred == nil {
+				t.Errorf("NewCredential() = nil, want a credential")
+			}
+		})
+	}
+}
+
+func TestNewCloudConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		cloud   string
+		wantErr bool
+	}{
+		{
+			name:  "Test public cloud",
+			cloud: azure.AzurePublicCloud,
+		},
+		{
+			name:  "Test sovereign cloud",
+			cloud: azure.AzureChinaCloud,
+		},
+		{
+			name:    "Test invalid cloud",
+			cloud:   "AzureGermany",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set(CloudChoice.Name, tt.cloud)
+			t.Cleanup(viper.Reset)
+
+			if _, err := NewCloudConfiguration(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("NewCloudConfiguration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type PolicyFormat string
+
+const (
+	PolicyJSONFormat      PolicyFormat = "json"
+	PolicyBicepFormat     PolicyFormat = "bicep"
+	PolicyTerraformFormat PolicyFormat = "terraform"
+)
+
+// The effects of the allowed locations policy
+const (
+	DenyEffect  = "deny"
+	AuditEffect = "audit"
+)
+
+// PolicyEffects lists every supported policy effect.
+var PolicyEffects = []string{DenyEffect, AuditEffect}
+
+const (
+	policyName        = "arv-allowed-locations"
+	policyDisplayName = "Allowed locations verified by azure-resource-verifier"
+	policyDescription = "Restricts the locations resources can be deployed to, to the locations verified by azure-resource-verifier."
+
+	// The API version of the policy definition resource of the Bicep snippet
+	policyDefinitionApiVersion = "2021-06-01"
+)
+
+// PolicyFormatFromFile returns the format of a policy file from its extension: .json, .bicep or .tf.
+func PolicyFormatFromFile(path string) (PolicyFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return PolicyJSONFormat, nil
+	case ".bicep":
+		return PolicyBicepFormat, nil
+	case ".tf":
+		return PolicyTerraformFormat, nil
+	default:
+		return "", fmt.Errorf("invalid policy file %s: the extension must be .json, .bicep or .tf", path)
+	}
+}
+
+// AllowedLocationsPolicy is a custom Azure Policy definition that only allows the given locations.
+type AllowedLocationsPolicy struct {
+	Locations []string
+	Effect    string
+}
+
+// NewAllowedLocationsPolicy returns the policy of the locations. An error is returned when there are no locations,
+// a policy without allowed locations would deny or flag every deployment.
+func NewAllowedLocationsPolicy(locations []string, effect string) (*AllowedLocationsPolicy, error) {
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("no location qualifies for the allowed locations policy")
+	}
+
+	if effect != DenyEffect && effect != AuditEffect {
+		return nil, fmt.Errorf("invalid policy effect %s: supported effects are %s", effect, strings.Join(PolicyEffects, ", "))
+	}
+
+	return &AllowedLocationsPolicy{Locations: locations, Effect: effect}, nil
+}
+
+// parameters returns the parameters of the definition. The verified locations and the effect are the defaults,
+// so the definition can be assigned without parameters.
+func (p *AllowedLocationsPolicy) parameters() map[string]any {
+	locations := append([]string{}, p.Locations...)
+
+	return map[string]any{
+		"listOfAllowedLocations": map[string]any{
+			"type": "Array",
+			"metadata": map[string]any{
+				"displayName": "Allowed locations",
+				"description": "The list of locations that can be specified when deploying resources.",
+				"strongType":  "location",
+			},
+			"defaultValue": locations,
+		},
+		"effect": map[string]any{
+			"type": "String",
+			"metadata": map[string]any{
+				"displayName": "Effect",
+				"description": "Deny, Audit or Disable the execution of the policy",
+			},
+			"allowedValues": []string{"Deny", "Audit", "Disabled"},
+			"defaultValue":  capitalize(p.Effect),
+		},
+	}
+}
+
+// policyRule returns the rule of the definition. It follows the built-in allowed locations definition,
+// the global resources are not restricted.
+func (p *AllowedLocationsPolicy) policyRule() map[string]any {
+	return map[string]any{
+		"if": map[string]any{
+			"allOf": []any{
+				map[string]any{"field": "location", "notIn": "[parameters('listOfAllowedLocations')]"},
+				map[string]any{"field": "location", "notEquals": "global"},
+				map[string]any{"field": "type", "notEquals": "Microsoft.AzureActiveDirectory/b2cDirectories"},
+			},
+		},
+		"then": map[string]any{
+			"effect": "[parameters('effect')]",
+		},
+	}
+}
+
+func (p *AllowedLocationsPolicy) properties() map[string]any {
+	return map[string]any{
+		"displayName": policyDisplayName,
+		"description": policyDescription,
+		"policyType":  "Custom",
+		"mode":        "Indexed",
+		"parameters":  p.parameters(),
+		"policyRule":  p.policyRule(),
+	}
+}
+
+// Write writes the policy definition in the format.
+func (p *AllowedLocationsPolicy) Write(w io.Writer, format PolicyFormat) error {
+	switch format {
+	case PolicyJSONFormat:
+		return p.writeJSON(w)
+	case PolicyBicepFormat:
+		return p.writeBicep(w)
+	case PolicyTerraformFormat:
+		return p.writeTerraform(w)
+	default:
+		return fmt.Errorf("invalid policy format: %s", format)
+	}
+}
+
+// writeJSON writes the definition in the format of the Azure Policy definitions, e.g. for az policy definition create.
+func (p *AllowedLocationsPolicy) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(map[string]any{
+		"name":       policyName,
+		"properties": p.properties(),
+	})
+}
+
+func (p *AllowedLocationsPolicy) writeBicep(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("targetScope = 'subscription'\n\n")
+	fmt.Fprintf(&b, "resource allowedLocations 'Microsoft.Authorization/policyDefinitions@%s' = {\n", policyDefinitionApiVersion)
+	fmt.Fprintf(&b, "  name: %s\n", bicepString(policyName))
+	b.WriteString("  properties: ")
+	writeBicepValue(&b, p.properties(), "  ")
+	b.WriteString("\n}\n\n")
+	b.WriteString("output policyDefinitionId string = allowedLocations.id\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (p *AllowedLocationsPolicy) writeTerraform(w io.Writer) error {
+	parameters, err := json.MarshalIndent(p.parameters(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	policyRule, err := json.MarshalIndent(p.policyRule(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("resource \"azurerm_policy_definition\" \"allowed_locations\" {\n")
+	fmt.Fprintf(&b, "  name         = %q\n", policyName)
+	b.WriteString("  policy_type  = \"Custom\"\n")
+	b.WriteString("  mode         = \"Indexed\"\n")
+	fmt.Fprintf(&b, "  display_name = %q\n", policyDisplayName)
+	fmt.Fprintf(&b, "  description  = %q\n\n", policyDescription)
+	fmt.Fprintf(&b, "  parameters = <<PARAMETERS\n%s\nPARAMETERS\n\n", parameters)
+	fmt.Fprintf(&b, "  policy_rule = <<POLICY_RULE\n%s\nPOLICY_RULE\n", policyRule)
+	b.WriteString("}\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// writeBicepValue writes a value decoded from json as a Bicep expression. The keys of the objects are sorted.
+func writeBicepValue(b *strings.Builder, value any, indent string) {
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b.WriteString("{\n")
+		for _, key := range keys {
+			fmt.Fprintf(b, "%s  %s: ", indent, key)
+			writeBicepValue(b, v[key], indent+"  ")
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "%s}", indent)
+	case []any:
+		b.WriteString("[\n")
+		for _, item := range v {
+			fmt.Fprintf(b, "%s  ", indent)
+			writeBicepValue(b, item, indent+"  ")
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "%s]", indent)
+	case []string:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		writeBicepValue(b, items, indent)
+	case string:
+		b.WriteString(bicepString(v))
+	default:
+		fmt.Fprintf(b, "%v", v)
+	}
+}
+
+// bicepString quotes a string for Bicep. Bicep escapes the policy expressions, e.g. [parameters('effect')],
+// when it compiles the template, so they are kept as is.
+func bicepString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	value = strings.ReplaceAll(value, "${", `\${`)
+	return "'" + value + "'"
+}
+
+func capitalize(value string) string {
+	if value == "" {
+		return value
+	}
+	return strings.ToUpper(value[:1]) + value[1:]
+}
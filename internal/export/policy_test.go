@@ -0,0 +1,138 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAllowedLocationsPolicy_Write(t *testing.T) {
+	policy, err := NewAllowedLocationsPolicy([]string{"eastus2", "westus3"}, AuditEffect)
+	if err != nil {
+		t.Fatalf("NewAllowedLocationsPolicy() error = %v", err)
+	}
+
+	t.Run("Test json", func(t *testing.T) {
+		var b bytes.Buffer
+		if err := policy.Write(&b, PolicyJSONFormat); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		var definition struct {
+			Properties struct {
+				Parameters struct {
+					ListOfAllowedLocations struct {
+						DefaultValue []string `json:"defaultValue"`
+					} `json:"listOfAllowedLocations"`
+					Effect struct {
+						DefaultValue string `json:"defaultValue"`
+					} `json:"effect"`
+				} `json:"parameters"`
+				PolicyRule struct {
+					Then struct {
+						Effect string `json:"effect"`
+					} `json:"then"`
+				} `json:"policyRule"`
+			} `json:"properties"`
+		}
+		if err := json.Unmarshal(b.Bytes(), &definition); err != nil {
+			t.Fatalf("Write() wrote invalid json: %v", err)
+		}
+
+		if got := definition.Properties.Parameters.ListOfAllowedLocations.DefaultValue; !slices.Equal(got, []string{"eastus2", "westus3"}) {
+			t.Errorf("Write() locations = %v, want [eastus2 westus3]", got)
+		}
+		if got := definition.Properties.Parameters.Effect.DefaultValue; got != "Audit" {
+			t.Errorf("Write() effect = %s, want Audit", got)
+		}
+		if got := definition.Properties.PolicyRule.Then.Effect; got != "[parameters('effect')]" {
+			t.Errorf("Write() rule effect = %s, want [parameters('effect')]", got)
+		}
+	})
+
+	t.Run("Test bicep", func(t *testing.T) {
+		var b bytes.Buffer
+		if err := policy.Write(&b, PolicyBicepFormat); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		for _, want := range []string{"resource allowedLocations 'Microsoft.Authorization/policyDefinitions@", "'eastus2'", `'[parameters(\'effect\')]'`, "defaultValue: 'Audit'"} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("Write() bicep doesn't contain %s:\n%s", want, b.String())
+			}
+		}
+	})
+
+	t.Run("Test terraform", func(t *testing.T) {
+		var b bytes.Buffer
+		if err := policy.Write(&b, PolicyTerraformFormat); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		for _, want := range []string{`resource "azurerm_policy_definition" "allowed_locations"`, `"westus3"`, "policy_rule = <<POLICY_RULE"} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("Write() terraform doesn't contain %s:\n%s", want, b.String())
+			}
+		}
+	})
+}
+
+func TestPolicyFormatFromFile(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    PolicyFormat
+		wantErr bool
+	}{
+		{path: "policy.json", want: PolicyJSONFormat},
+		{path: "infra/policy.bicep", want: PolicyBicepFormat},
+		{path: "policy.TF", want: PolicyTerraformFormat},
+		{path: "policy.yaml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := PolicyFormatFromFile(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PolicyFormatFromFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PolicyFormatFromFile() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAllowedLocationsPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		locations []string
+		effect    string
+		wantErr   bool
+	}{
+		{
+			name:      "Test deny",
+			locations: []string{"eastus2"},
+			effect:    DenyEffect,
+		},
+		{
+			name:      "Test no locations",
+			locations: []string{},
+			effect:    DenyEffect,
+			wantErr:   true,
+		},
+		{
+			name:      "Test invalid effect",
+			locations: []string{"eastus2"},
+			effect:    "Disabled",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAllowedLocationsPolicy(tt.locations, tt.effect); (err != nil) != tt.wantErr {
+				t.Errorf("NewAllowedLocationsPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
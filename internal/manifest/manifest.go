@@ -0,0 +1,135 @@
+package manifest
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/spf13/viper"
+)
+
+// DefaultFileName is the name of the manifest file used by convention
+const DefaultFileName = "arv.yaml"
+
+const schemaURL = "https://github.com/nickdala/azure-resource-verifier/arv.schema.json"
+
+//go:embed schema.json
+var schemaJSON []byte
+
+// The high availability modes of Azure Database for PostgreSQL and MySQL Flexible Server
+const (
+	HaDisabled      = "disabled"
+	HaSameZone      = "same-zone"
+	HaZoneRedundant = "zone-redundant"
+)
+
+// Manifest describes the services of a workload and the constraints they must satisfy.
+type Manifest struct {
+	Locations   []string    `mapstructure:"locations"`
+	Constraints Constraints `mapstructure:"constraints"`
+	Services    []Service   `mapstructure:"services"`
+}
+
+type Constraints struct {
+	ZoneRedundant bool `mapstructure:"zone-redundant"`
+}
+
+// Service is a service of the workload. Only the options of the service type are set.
+type Service struct {
+	Type string `mapstructure:"type"`
+	Name string `mapstructure:"name"`
+
+	// Azure Cache for Redis
+	Sku string `mapstructure:"sku"`
+
+	// Azure Database for PostgreSQL and MySQL Flexible Server
+	Version          string `mapstructure:"version"`
+	HighAvailability string `mapstructure:"high-availability"`
+
+	// Azure SQL Database
+	Edition          string `mapstructure:"edition"`
+	ServiceObjective string `mapstructure:"service-objective"`
+
+	// Azure Virtual Machines
+	Size string `mapstructure:"size"`
+
+	// The vCPUs of the Virtual Machines or the AKS nodes, verified against the regional vCPU quota
+	Cores int64 `mapstructure:"cores"`
+
+	// Azure Kubernetes Service
+	KubernetesVersion string   `mapstructure:"kubernetes-version"`
+	NodeVmSize        string   `mapstructure:"node-vm-size"`
+	Zones             []string `mapstructure:"zones"`
+
+	// Any resource type of a resource provider
+	ResourceType string `mapstructure:"resource-type"`
+
+	// Azure App Service
+	OperatingSystem string `mapstructure:"operating-system"`
+	PublishType     string `mapstructure:"publish-type"`
+	PlanSku         string `mapstructure:"plan-sku"`
+}
+
+// Load reads the manifest from a yaml or json file and validates it against the manifest schema.
+func Load(path string) (*Manifest, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read the manifest %s: %w", path, err)
+	}
+
+	if err := Validate(v.AllSettings()); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	manifest := &Manifest{}
+	if err := v.Unmarshal(manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest %s: %w", path, err)
+	}
+
+	// The locations can be given by their display name, e.g. East US 2
+	for i, location := range manifest.Locations {
+		manifest.Locations[i] = azure.NormalizeLocation(location)
+	}
+
+	return manifest, nil
+}
+
+// Validate validates a decoded manifest against the manifest schema.
+func Validate(document map[string]any) error {
+	schema, err := compileSchema()
+	if err != nil {
+		return err
+	}
+
+	// Round trip the document through json so it only contains json types
+	data, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to encode the manifest: %w", err)
+	}
+
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode the manifest: %w", err)
+	}
+
+	return schema.Validate(instance)
+}
+
+func compileSchema() (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the manifest schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("failed to load the manifest schema: %w", err)
+	}
+
+	return compiler.Compile(schemaURL)
+}
@@ -0,0 +1,94 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeManifest(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write the manifest: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeManifest(t, "arv.yaml", `
+locations:
+  - eastus2
+  - West US 3
+constraints:
+  zone-redundant: true
+services:
+  - type: redis
+    sku: Premium
+  - type: postgresql
+    version: "16"
+    high-availability: zone-redundant
+  - type: webapp
+    operating-system: linux
+    publish-type: container
+    plan-sku: P1v3
+`)
+
+	manifest, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(manifest.Locations, []string{"eastus2", "westus3"}) {
+		t.Errorf("Load() locations = %v, want [eastus2 westus3]", manifest.Locations)
+	}
+	if !manifest.Constraints.ZoneRedundant {
+		t.Errorf("Load() zone-redundant = false, want true")
+	}
+	if len(manifest.Services) != 3 {
+		t.Fatalf("Load() services = %v, want 3 services", manifest.Services)
+	}
+	if got := manifest.Services[1].Version; got != "16" {
+		t.Errorf("Load() postgresql version = %s, want 16", got)
+	}
+	if got := manifest.Services[2].PlanSku; got != "P1v3" {
+		t.Errorf("Load() webapp plan-sku = %s, want P1v3", got)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "Test missing services",
+			content: "locations: [eastus]\n",
+		},
+		{
+			name:    "Test unknown service type",
+			content: "services:\n  - type: mongodb\n",
+		},
+		{
+			name:    "Test option of another service type",
+			content: "services:\n  - type: redis\n    plan-sku: P1v3\n",
+		},
+		{
+			name:    "Test unquoted version",
+			content: "services:\n  - type: mysql\n    version: 8.0\n",
+		},
+		{
+			name:    "Test invalid high availability mode",
+			content: "services:\n  - type: postgresql\n    high-availability: always\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeManifest(t, "arv.yaml", tt.content)
+			if _, err := Load(path); err == nil {
+				t.Errorf("Load() expected an error")
+			}
+		})
+	}
+}
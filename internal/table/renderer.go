@@ -0,0 +1,131 @@
+package table
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/olekukonko/tablewriter"
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	TableFormat Format = "table"
+	JSONFormat  Format = "json"
+	YAMLFormat  Format = "yaml"
+	CSVFormat   Format = "csv"
+)
+
+// Formats lists every supported output format.
+var Formats = []string{string(TableFormat), string(JSONFormat), string(YAMLFormat), string(CSVFormat)}
+
+// Renderer writes a table to an output stream in a specific format.
+type Renderer interface {
+	Render(w io.Writer, t *Table) error
+}
+
+func NewRenderer(format Format) (Renderer, error) {
+	switch format {
+	case TableFormat, "":
+		return &asciiRenderer{}, nil
+	case JSONFormat:
+		return &jsonRenderer{}, nil
+	case YAMLFormat:
+		return &yamlRenderer{}, nil
+	case CSVFormat:
+		return &csvRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("invalid output format: %s", format)
+	}
+}
+
+type asciiRenderer struct{}
+
+func (r *asciiRenderer) Render(w io.Writer, t *Table) error {
+	writer := tablewriter.NewWriter(w)
+	t.applyLayout(writer)
+	writer.AppendBulk(t.rows)
+	writer.Render()
+	return nil
+}
+
+type jsonRenderer struct{}
+
+func (r *jsonRenderer) Render(w io.Writer, t *Table) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(structured(t))
+}
+
+type yamlRenderer struct{}
+
+func (r *yamlRenderer) Render(w io.Writer, t *Table) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(structured(t)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+type csvRenderer struct{}
+
+func (r *csvRenderer) Render(w io.Writer, t *Table) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(t.header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(t.rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// structured returns the data of the table if set, otherwise the rows as records.
+func structured(t *Table) any {
+	if t.data != nil {
+		return t.data
+	}
+	return records(t)
+}
+
+// records converts the rows of the table into a list of objects keyed by the
+// camel cased column headers, e.g. "Display Name" becomes "displayName".
+func records(t *Table) []map[string]string {
+	keys := make([]string, len(t.header))
+	for i, column := range t.header {
+		keys[i] = fieldName(column)
+	}
+
+	result := make([]map[string]string, 0, len(t.rows))
+	for _, row := range t.rows {
+		record := make(map[string]string, len(keys))
+		for i, key := range keys {
+			if i < len(row) {
+				record[key] = row[i]
+			}
+		}
+		result = append(result, record)
+	}
+
+	return result
+}
+
+func fieldName(column string) string {
+	var b strings.Builder
+	for i, word := range strings.Fields(column) {
+		if i == 0 {
+			b.WriteString(strings.ToLower(word))
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package table
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTable_Render(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		want   string
+	}{
+		{
+			name:   "Test JSON output",
+			format: JSONFormat,
+			want: `[
+  {
+    "details": "offered in this location",
+    "displayName": "East US",
+    "enabled": "true",
+    "location": "eastus",
+    "reason": ""
+  }
+]
+`,
+		},
+		{
+			name:   "Test YAML output",
+			format: YAMLFormat,
+			want: `- details: offered in this location
+  displayName: East US
+  enabled: "true"
+  location: eastus
+  reason: ""
+`,
+		},
+		{
+			name:   "Test CSV output",
+			format: CSVFormat,
+			want:   "Location,Display Name,Enabled,Reason,Details\neastus,East US,true,,offered in this location\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := NewTable(RedisService)
+			table.AppendRow([]string{"eastus", "East US", "true", "", "offered in this location"})
+
+			var out bytes.Buffer
+			if err := table.Render(&out, tt.format); err != nil {
+				t.Fatalf("Table.Render() error = %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("Table.Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRenderer_InvalidFormat(t *testing.T) {
+	if _, err := NewRenderer("xml"); err == nil {
+		t.Errorf("NewRenderer() expected an error for an invalid format")
+	}
+}
@@ -1,79 +1,140 @@
 package table
 
 import (
-	"os"
+	"io"
 
 	"github.com/olekukonko/tablewriter"
 )
 
 type Table struct {
-	table *tablewriter.Table
+	layout TableLayout
+	header []string
+	rows   [][]string
+	data   any
 }
 
 type TableLayout string
 
 const (
-	Locations         TableLayout = "locations"
-	PostgreSqlService TableLayout = "postgresql_service"
-	RedisService      TableLayout = "redis_service"
-	WebApp            TableLayout = "web_app"
-	MultipleServices  TableLayout = "multiple_services"
+	Locations             TableLayout = "locations"
+	PostgreSqlService     TableLayout = "postgresql_service"
+	MySqlService          TableLayout = "mysql_service"
+	SqlDatabaseService    TableLayout = "sql_database_service"
+	RedisService          TableLayout = "redis_service"
+	WebApp                TableLayout = "web_app"
+	VirtualMachineSku     TableLayout = "virtual_machine_sku"
+	KubernetesService     TableLayout = "kubernetes_service"
+	ResourceType          TableLayout = "resource_type"
+	Quota                 TableLayout = "quota"
+	Template              TableLayout = "template"
+	MultipleServices      TableLayout = "multiple_services"
+	MultipleSubscriptions TableLayout = "multiple_subscriptions"
+	Subscriptions         TableLayout = "subscriptions"
+	Doctor                TableLayout = "doctor"
+	CacheInfo             TableLayout = "cache_info"
 )
 
 func NewTable(layout TableLayout) *Table {
-	t := tablewriter.NewWriter(os.Stdout)
+	t := &Table{layout: layout}
 
 	switch layout {
 	case Locations:
-		locationsLayout(t)
+		t.header = []string{"Name", "Display Name"}
 	case PostgreSqlService:
-		singleServiceLayout(t)
+		t.header = []string{"Location", "Display Name", "Enabled", "HA Enabled", "Reason", "Details", "Quota Headroom"}
+	case MySqlService:
+		t.header = []string{"Location", "Display Name", "Enabled", "HA Enabled", "Reason", "Details"}
+	case SqlDatabaseService:
+		t.header = []string{"Location", "Display Name", "Enabled", "Status", "Zone Redundant", "Serverless", "Reason", "Details"}
+	case VirtualMachineSku:
+		t.header = []string{"Location", "Display Name", "Enabled", "Status", "Zones", "Reason", "Details", "Quota Headroom"}
+	case KubernetesService:
+		t.header = []string{"Location", "Display Name", "Enabled", "Zone Redundant", "Reason", "Details", "Quota Headroom"}
+	case ResourceType:
+		t.header = []string{"Location", "Display Name", "Enabled", "Zones", "Latest API Version", "Reason", "Details"}
+	case Quota:
+		t.header = []string{"Location", "Provider", "Name", "Current", "Limit", "Remaining"}
+	case Template:
+		t.header = []string{"Resource", "Type", "API Version", "Location", "Enabled", "Reason", "Details"}
 	case WebApp:
-		webAppLayout(t)
+		t.header = []string{"Location", "Display Name", "Enabled", "Reason", "Details"}
 	case RedisService:
-		redisLayout(t)
+		t.header = []string{"Location", "Display Name", "Enabled", "Reason", "Details"}
 	case MultipleServices:
-		multipleServiceLayout(t)
+		t.header = []string{"Service", "Location", "Enabled", "HA Enabled", "Reason", "Details", "Quota Headroom"}
+	case MultipleSubscriptions:
+		t.header = []string{"Subscription", "Service", "Location", "Enabled", "Reason", "Details", "Quota Headroom"}
+	case Subscriptions:
+		t.header = []string{"Subscription ID", "Name", "State", "Tenant", "Default"}
+	case Doctor:
+		t.header = []string{"Check", "Status", "Details"}
+	case CacheInfo:
+		t.header = []string{"Directory", "Entries", "Expired", "Size"}
 	}
 
-	return &Table{table: t}
+	return t
 }
 
-func redisLayout(t *tablewriter.Table) {
-	t.SetHeader([]string{"Location", "Display Name", "Enabled"})
-}
-
-func webAppLayout(t *tablewriter.Table) {
-	t.SetHeader([]string{"Location", "Display Name", "Enabled"})
-}
+// applyLayout configures the ASCII table writer for the layout of the table.
+func (t *Table) applyLayout(w *tablewriter.Table) {
+	w.SetHeader(t.header)
 
-func locationsLayout(t *tablewriter.Table) {
-	t.SetHeader([]string{"Name", "Display Name"})
+	switch t.layout {
+	case PostgreSqlService, MySqlService, SqlDatabaseService, VirtualMachineSku, KubernetesService, ResourceType, Template, Doctor:
+		singleServiceLayout(w)
+	case MultipleServices:
+		multipleServiceLayout(w)
+	case MultipleSubscriptions:
+		multipleSubscriptionLayout(w)
+	}
 }
 
 func singleServiceLayout(t *tablewriter.Table) {
-	t.SetHeader([]string{"Location", "Display Name", "Enabled", "HA Enabled", "Reason"})
 	t.SetAutoWrapText(true)
 }
 
 func multipleServiceLayout(t *tablewriter.Table) {
-	t.SetHeader([]string{"Service", "Location", "Enabled", "HA Enabled", "Reason"})
 	t.SetAutoMergeCellsByColumnIndex([]int{0})
 	t.SetAutoWrapText(true)
 }
 
+func multipleSubscriptionLayout(t *tablewriter.Table) {
+	t.SetAutoMergeCellsByColumnIndex([]int{0, 1})
+	t.SetAutoWrapText(true)
+}
+
 func (t *Table) SetHeader(header []string) {
-	t.table.SetHeader(header)
+	t.header = header
+}
+
+func (t *Table) Header() []string {
+	return t.header
+}
+
+func (t *Table) Rows() [][]string {
+	return t.rows
+}
+
+// SetData sets the structured data behind the rows. If set, the data is written
+// by the json and yaml renderers instead of the rows.
+func (t *Table) SetData(data any) {
+	t.data = data
 }
 
 func (t *Table) AppendRow(row []string) {
-	t.table.Append(row)
+	t.rows = append(t.rows, row)
 }
 
 func (t *Table) AppendBulk(rows [][]string) {
-	t.table.AppendBulk(rows)
+	t.rows = append(t.rows, rows...)
 }
 
-func (t *Table) Render() {
-	t.table.Render()
+// Render writes the table to w in the given output format.
+func (t *Table) Render(w io.Writer, format Format) error {
+	renderer, err := NewRenderer(format)
+	if err != nil {
+		return err
+	}
+
+	return renderer.Render(w, t)
 }
@@ -0,0 +1,248 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/manifest"
+)
+
+// The name of the azurerm provider in the provider addresses, e.g. registry.terraform.io/hashicorp/azurerm
+const azurermProvider = "azurerm"
+
+// PlannedResource is an azurerm resource of a plan, described as a service of a workload manifest.
+type PlannedResource struct {
+	Address string
+	Type    string
+	// The location of the resource, empty if the resource has no location, e.g. azurerm_mssql_database
+	Location      string
+	Service       manifest.Service
+	ZoneRedundant bool
+}
+
+// Plan is the azurerm resources a Terraform plan creates or updates.
+type Plan struct {
+	Resources []*PlannedResource
+	// The addresses of the azurerm resources that have no service verification
+	Unverified []string
+}
+
+// The parts of the terraform show -json output used by the verification
+type planDocument struct {
+	FormatVersion   string `json:"format_version"`
+	ResourceChanges []struct {
+		Address      string `json:"address"`
+		Mode         string `json:"mode"`
+		Type         string `json:"type"`
+		ProviderName string `json:"provider_name"`
+		Change       struct {
+			Actions []string       `json:"actions"`
+			After   map[string]any `json:"after"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// LoadPlan reads a Terraform plan in the JSON format, i.e. the output of terraform show -json.
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the plan %s: %w", path, err)
+	}
+
+	plan, err := ParsePlan(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the plan %s: %w", path, err)
+	}
+
+	return plan, nil
+}
+
+// ParsePlan parses a Terraform plan in the JSON format. Only the managed azurerm resources that are created
+// or updated are kept, the resources that are deleted or unchanged can't fail.
+func ParsePlan(data []byte) (*Plan, error) {
+	document := &planDocument{}
+	if err := json.Unmarshal(data, document); err != nil {
+		return nil, err
+	}
+
+	if document.FormatVersion == "" {
+		return nil, fmt.Errorf("not a terraform plan: use the output of terraform show -json")
+	}
+
+	plan := &Plan{}
+	for _, change := range document.ResourceChanges {
+		if change.Mode != "managed" || !isAzurermProvider(change.ProviderName) || change.Change.After == nil {
+			continue
+		}
+
+		if !slices.Contains(change.Change.Actions, "create") && !slices.Contains(change.Change.Actions, "update") {
+			continue
+		}
+
+		resource := newPlannedResource(change.Address, change.Type, change.Change.After)
+		if resource == nil {
+			plan.Unverified = append(plan.Unverified, change.Address)
+			continue
+		}
+		plan.Resources = append(plan.Resources, resource)
+	}
+
+	return plan, nil
+}
+
+// Locations returns the distinct locations of the planned resources.
+func (p *Plan) Locations() []string {
+	locations := []string{}
+	for _, resource := range p.Resources {
+		if resource.Location != "" && !slices.Contains(locations, resource.Location) {
+			locations = append(locations, resource.Location)
+		}
+	}
+	return locations
+}
+
+// newPlannedResource maps an azurerm resource to the service that verifies it, or returns nil
+// if the resource type has no service verification.
+func newPlannedResource(address string, resourceType string, values map[string]any) *PlannedResource {
+	resource := &PlannedResource{
+		Address:  address,
+		Type:     resourceType,
+		Location: azure.NormalizeLocation(stringValue(values, "location")),
+		Service:  manifest.Service{Name: address},
+	}
+
+	switch resourceType {
+	case "azurerm_redis_cache":
+		resource.Service.Type = azure.RedisService
+		resource.Service.Sku = stringValue(values, "sku_name")
+		resource.ZoneRedundant = len(stringValues(values, "zones")) > 1
+	case "azurerm_postgresql_flexible_server":
+		resource.Service.Type = azure.PostgresqlService
+		resource.Service.Version = stringValue(values, "version")
+		resource.Service.HighAvailability = highAvailability(values)
+	case "azurerm_mysql_flexible_server":
+		resource.Service.Type = azure.MysqlService
+		resource.Service.Version = stringValue(values, "version")
+		resource.Service.HighAvailability = highAvailability(values)
+	case "azurerm_mssql_database":
+		resource.Service.Type = azure.SqlDatabaseService
+		resource.Service.ServiceObjective = stringValue(values, "sku_name")
+		resource.ZoneRedundant = boolValue(values, "zone_redundant")
+	case "azurerm_linux_virtual_machine", "azurerm_windows_virtual_machine":
+		resource.Service.Type = azure.VirtualMachineSkuService
+		resource.Service.Size = stringValue(values, "size")
+	case "azurerm_virtual_machine":
+		resource.Service.Type = azure.VirtualMachineSkuService
+		resource.Service.Size = stringValue(values, "vm_size")
+	case "azurerm_linux_virtual_machine_scale_set", "azurerm_windows_virtual_machine_scale_set":
+		resource.Service.Type = azure.VirtualMachineSkuService
+		resource.Service.Size = stringValue(values, "sku")
+		resource.ZoneRedundant = len(stringValues(values, "zones")) > 1
+	case "azurerm_kubernetes_cluster":
+		resource.Service.Type = azure.KubernetesService
+		resource.Service.KubernetesVersion = stringValue(values, "kubernetes_version")
+		if pool := firstBlock(values, "default_node_pool"); pool != nil {
+			resource.Service.NodeVmSize = stringValue(pool, "vm_size")
+			resource.Service.Zones = stringValues(pool, "zones")
+		}
+	case "azurerm_service_plan":
+		resource.Service.Type = azure.WebAppService
+		resource.Service.OperatingSystem = strings.ToLower(stringValue(values, "os_type"))
+		resource.Service.PlanSku = stringValue(values, "sku_name")
+		resource.ZoneRedundant = boolValue(values, "zone_balancing_enabled")
+		// The plans of the other operating systems, e.g. WindowsContainer, and the SKUs of other tiers,
+		// e.g. Y1 for the consumption plan of Azure Functions, are verified with the defaults
+		if _, err := azure.AppServiceOSFromString(resource.Service.OperatingSystem); err != nil {
+			resource.Service.OperatingSystem = ""
+		}
+		if _, err := azure.AppServicePlanTierFromSku(resource.Service.PlanSku); err != nil {
+			log.Printf("The SKU %s of %s can't be verified", resource.Service.PlanSku, address)
+			resource.Service.PlanSku = ""
+		}
+	case "azurerm_linux_web_app", "azurerm_windows_web_app":
+		resource.Service.Type = azure.WebAppService
+		resource.Service.OperatingSystem = strings.TrimSuffix(strings.TrimPrefix(resourceType, "azurerm_"), "_web_app")
+		resource.Service.PublishType = webAppPublishType(values)
+	default:
+		if strings.HasPrefix(resourceType, "azurerm_") {
+			log.Printf("The resource type %s of %s has no verification", resourceType, address)
+		}
+		return nil
+	}
+
+	// The size is required to verify a virtual machine, it is unknown until apply if it's computed
+	if resource.Service.Type == azure.VirtualMachineSkuService && resource.Service.Size == "" {
+		return nil
+	}
+
+	return resource
+}
+
+// highAvailability returns the high availability mode of a flexible server, e.g. ZoneRedundant, as a manifest option.
+func highAvailability(values map[string]any) string {
+	block := firstBlock(values, "high_availability")
+	if block == nil {
+		return ""
+	}
+
+	switch stringValue(block, "mode") {
+	case "ZoneRedundant":
+		return manifest.HaZoneRedundant
+	case "SameZone":
+		return manifest.HaSameZone
+	default:
+		return ""
+	}
+}
+
+// webAppPublishType returns container if the web app runs a Docker image, code otherwise.
+func webAppPublishType(values map[string]any) string {
+	if siteConfig := firstBlock(values, "site_config"); siteConfig != nil {
+		if stack := firstBlock(siteConfig, "application_stack"); stack != nil && stringValue(stack, "docker_image_name") != "" {
+			return "container"
+		}
+	}
+	return "code"
+}
+
+func isAzurermProvider(providerName string) bool {
+	return providerName == azurermProvider || strings.HasSuffix(providerName, "/"+azurermProvider)
+}
+
+func stringValue(values map[string]any, key string) string {
+	value, _ := values[key].(string)
+	return value
+}
+
+func boolValue(values map[string]any, key string) bool {
+	value, _ := values[key].(bool)
+	return value
+}
+
+func stringValues(values map[string]any, key string) []string {
+	list, _ := values[key].([]any)
+
+	result := []string{}
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// firstBlock returns the first nested block, e.g. default_node_pool, which terraform encodes as a list.
+func firstBlock(values map[string]any, key string) map[string]any {
+	list, _ := values[key].([]any)
+	if len(list) == 0 {
+		return nil
+	}
+
+	block, _ := list[0].(map[string]any)
+	return block
+}
@@ -0,0 +1,184 @@
+package terraform
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/nickdala/azure-resource-verifier/internal/azure"
+	"github.com/nickdala/azure-resource-verifier/internal/manifest"
+)
+
+const testPlan = `{
+  "format_version": "1.2",
+  "terraform_version": "1.9.5",
+  "resource_changes": [
+    {
+      "address": "azurerm_resource_group.rg",
+      "mode": "managed",
+      "type": "azurerm_resource_group",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {"actions": ["create"], "after": {"location": "eastus2", "name": "rg"}}
+    },
+    {
+      "address": "azurerm_postgresql_flexible_server.db",
+      "mode": "managed",
+      "type": "azurerm_postgresql_flexible_server",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {
+        "actions": ["create"],
+        "after": {
+          "location": "East US 2",
+          "sku_name": "GP_Standard_D4s_v3",
+          "version": "16",
+          "high_availability": [{"mode": "ZoneRedundant", "standby_availability_zone": "2"}]
+        }
+      }
+    },
+    {
+      "address": "module.cache.azurerm_redis_cache.cache",
+      "mode": "managed",
+      "type": "azurerm_redis_cache",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {"actions": ["update"], "after": {"location": "westus3", "sku_name": "Premium", "zones": ["1", "2"]}}
+    },
+    {
+      "address": "azurerm_kubernetes_cluster.aks",
+      "mode": "managed",
+      "type": "azurerm_kubernetes_cluster",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {
+        "actions": ["delete", "create"],
+        "after": {
+          "location": "eastus2",
+          "kubernetes_version": "1.30",
+          "default_node_pool": [{"vm_size": "Standard_D4s_v5", "zones": ["1", "2", "3"]}]
+        }
+      }
+    },
+    {
+      "address": "azurerm_linux_web_app.app",
+      "mode": "managed",
+      "type": "azurerm_linux_web_app",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {
+        "actions": ["create"],
+        "after": {"location": "eastus2", "site_config": [{"application_stack": [{"docker_image_name": "nginx:latest"}]}]}
+      }
+    },
+    {
+      "address": "azurerm_service_plan.plan",
+      "mode": "managed",
+      "type": "azurerm_service_plan",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {"actions": ["create"], "after": {"location": "eastus2", "os_type": "Linux", "sku_name": "P1v3", "zone_balancing_enabled": true}}
+    },
+    {
+      "address": "azurerm_mssql_database.db",
+      "mode": "managed",
+      "type": "azurerm_mssql_database",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {"actions": ["create"], "after": {"sku_name": "GP_S_Gen5_2", "zone_redundant": true}}
+    },
+    {
+      "address": "azurerm_redis_cache.old",
+      "mode": "managed",
+      "type": "azurerm_redis_cache",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {"actions": ["delete"], "after": null}
+    },
+    {
+      "address": "azurerm_linux_virtual_machine.unchanged",
+      "mode": "managed",
+      "type": "azurerm_linux_virtual_machine",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {"actions": ["no-op"], "after": {"location": "eastus2", "size": "Standard_D2s_v5"}}
+    },
+    {
+      "address": "data.azurerm_client_config.current",
+      "mode": "data",
+      "type": "azurerm_client_config",
+      "provider_name": "registry.terraform.io/hashicorp/azurerm",
+      "change": {"actions": ["read"], "after": {}}
+    },
+    {
+      "address": "random_password.password",
+      "mode": "managed",
+      "type": "random_password",
+      "provider_name": "registry.terraform.io/hashicorp/random",
+      "change": {"actions": ["create"], "after": {"length": 16}}
+    }
+  ]
+}`
+
+func TestParsePlan(t *testing.T) {
+	plan, err := ParsePlan([]byte(testPlan))
+	if err != nil {
+		t.Fatalf("ParsePlan() error = %v", err)
+	}
+
+	want := []PlannedResource{
+		{
+			Address:  "azurerm_postgresql_flexible_server.db",
+			Type:     "azurerm_postgresql_flexible_server",
+			Location: "eastus2",
+			Service:  manifest.Service{Name: "azurerm_postgresql_flexible_server.db", Type: azure.PostgresqlService, Version: "16", HighAvailability: manifest.HaZoneRedundant},
+		},
+		{
+			Address:       "module.cache.azurerm_redis_cache.cache",
+			Type:          "azurerm_redis_cache",
+			Location:      "westus3",
+			Service:       manifest.Service{Name: "module.cache.azurerm_redis_cache.cache", Type: azure.RedisService, Sku: "Premium"},
+			ZoneRedundant: true,
+		},
+		{
+			Address:  "azurerm_kubernetes_cluster.aks",
+			Type:     "azurerm_kubernetes_cluster",
+			Location: "eastus2",
+			Service:  manifest.Service{Name: "azurerm_kubernetes_cluster.aks", Type: azure.KubernetesService, KubernetesVersion: "1.30", NodeVmSize: "Standard_D4s_v5", Zones: []string{"1", "2", "3"}},
+		},
+		{
+			Address:  "azurerm_linux_web_app.app",
+			Type:     "azurerm_linux_web_app",
+			Location: "eastus2",
+			Service:  manifest.Service{Name: "azurerm_linux_web_app.app", Type: azure.WebAppService, OperatingSystem: "linux", PublishType: "container"},
+		},
+		{
+			Address:       "azurerm_service_plan.plan",
+			Type:          "azurerm_service_plan",
+			Location:      "eastus2",
+			Service:       manifest.Service{Name: "azurerm_service_plan.plan", Type: azure.WebAppService, OperatingSystem: "linux", PlanSku: "P1v3"},
+			ZoneRedundant: true,
+		},
+		{
+			Address:       "azurerm_mssql_database.db",
+			Type:          "azurerm_mssql_database",
+			Service:       manifest.Service{Name: "azurerm_mssql_database.db", Type: azure.SqlDatabaseService, ServiceObjective: "GP_S_Gen5_2"},
+			ZoneRedundant: true,
+		},
+	}
+
+	if len(plan.Resources) != len(want) {
+		t.Fatalf("ParsePlan() got %d resources, want %d", len(plan.Resources), len(want))
+	}
+
+	for i, resource := range plan.Resources {
+		if !reflect.DeepEqual(*resource, want[i]) {
+			t.Errorf("ParsePlan() resource %d = %+v, want %+v", i, *resource, want[i])
+		}
+	}
+
+	if !slices.Equal(plan.Unverified, []string{"azurerm_resource_group.rg"}) {
+		t.Errorf("ParsePlan() unverified = %v, want [azurerm_resource_group.rg]", plan.Unverified)
+	}
+
+	if got := plan.Locations(); !slices.Equal(got, []string{"eastus2", "westus3"}) {
+		t.Errorf("Locations() = %v, want [eastus2 westus3]", got)
+	}
+}
+
+func TestParsePlan_NotAPlan(t *testing.T) {
+	if _, err := ParsePlan([]byte(`{"resources": []}`)); err == nil {
+		t.Errorf("ParsePlan() error = nil, want an error")
+	}
+}